@@ -0,0 +1,79 @@
+// Package cloud defines the abstraction boundary between the boss's worker
+// pool and the various places it can launch worker VMs. The interfaces here
+// are modeled after Arvados' lib/cloud package: a Driver knows how to build
+// an InstanceSet for a given configuration, and an InstanceSet knows how to
+// create/enumerate/destroy instances within that scope. Providers (gcp,
+// azure, DO, a stub for tests, ...) register a Driver by name so the worker
+// pool never needs to branch on Conf.Platform itself.
+package cloud
+
+import "log"
+
+// InstanceSetID scopes the instances managed by a single InstanceSet so that
+// Instances() can be filtered to just the ones we own, even if the
+// underlying account/project is shared with other things.
+type InstanceSetID string
+
+// InstanceTags are opaque key/value pairs stored on a cloud instance.
+// The boss uses well-known keys (see tagKeyInstanceSetID, tagKeyIdleBehavior)
+// to recover state after a restart.
+type InstanceTags map[string]string
+
+// Instance is a single cloud VM, already created.
+type Instance interface {
+	// ID is the provider-assigned identifier for this instance.
+	ID() string
+
+	// Address is the IP (or hostname) the boss should use to reach the
+	// worker running on this instance.
+	Address() string
+
+	// Tags returns the tags currently associated with the instance.
+	Tags() InstanceTags
+
+	// SetTags updates the tags stored on the instance. Implementations
+	// should merge rather than replace when the provider allows it.
+	SetTags(tags InstanceTags) error
+
+	// Destroy tears down the instance. It should be safe to call more
+	// than once.
+	Destroy() error
+}
+
+// InstanceSet manages a group of instances that share an InstanceSetID.
+type InstanceSet interface {
+	// Create launches a new instance of the given type/image, tagged
+	// with tags (which should include our InstanceSetID), running
+	// initScript on boot and trusting publicKey for SSH access.
+	Create(instanceType, imageID string, tags InstanceTags, initScript, publicKey string) (Instance, error)
+
+	// Instances returns every instance in this set whose tags are a
+	// superset of the given tags. Passing this InstanceSet's own
+	// InstanceSetID tag returns everything we own, which is how the
+	// sync loop discovers instances the boss didn't create itself.
+	Instances(tags InstanceTags) ([]Instance, error)
+}
+
+// Driver builds an InstanceSet bound to a particular provider account/region
+// as described by config. config is provider-specific (e.g. project ID and
+// zone for gcp, subscription/resource group for azure) and is passed through
+// unparsed from the open-lambda config file.
+type Driver interface {
+	InstanceSet(config map[string]interface{}, setID InstanceSetID, logger *log.Logger) (InstanceSet, error)
+}
+
+var drivers = map[string]Driver{}
+
+// Register makes a Driver available under name, e.g. "gcp", "azure", "do",
+// or "stub". It is normally called from an init() in the provider's package,
+// so importing that package for its side effects is enough to make the
+// platform available.
+func Register(name string, driver Driver) {
+	drivers[name] = driver
+}
+
+// Get looks up a previously-registered Driver by name.
+func Get(name string) (Driver, bool) {
+	driver, ok := drivers[name]
+	return driver, ok
+}
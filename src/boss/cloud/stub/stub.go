@@ -0,0 +1,125 @@
+// Package stub implements a cloud.Driver that never talks to a real
+// provider. It exists so boss unit tests (and local development without
+// cloud credentials) can exercise WorkerPool the same way Arvados'
+// test/stub_driver.go backs its worker pool tests: instances are just
+// bookkeeping in memory, "created" instantly and all reachable at the
+// loopback address 127.0.0.1.
+package stub
+
+import (
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/open-lambda/open-lambda/ol/boss/cloud"
+)
+
+func init() {
+	cloud.Register("stub", &Driver{})
+}
+
+// Driver is the stub cloud.Driver. It has no configuration of its own; every
+// InstanceSet it hands out is an independent in-memory set.
+type Driver struct{}
+
+func (d *Driver) InstanceSet(config map[string]interface{}, setID cloud.InstanceSetID, logger *log.Logger) (cloud.InstanceSet, error) {
+	return &instanceSet{setID: setID, logger: logger, instances: map[string]*instance{}}, nil
+}
+
+type instanceSet struct {
+	setID  cloud.InstanceSetID
+	logger *log.Logger
+
+	mu        sync.Mutex
+	nextID    int
+	instances map[string]*instance
+}
+
+func (s *instanceSet) Create(instanceType, imageID string, tags cloud.InstanceTags, initScript, publicKey string) (cloud.Instance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	id := fmt.Sprintf("stub-%d", s.nextID)
+
+	merged := cloud.InstanceTags{}
+	for k, v := range tags {
+		merged[k] = v
+	}
+
+	inst := &instance{
+		set: s,
+		id:  id,
+		// A bare host, per cloud.Instance.Address()'s contract -- callers
+		// like forwardTask/probe append the worker port themselves.
+		address: "127.0.0.1",
+		tags:    merged,
+	}
+	s.instances[id] = inst
+
+	if s.logger != nil {
+		s.logger.Printf("stub: created instance %s (set=%s)", id, s.setID)
+	}
+
+	return inst, nil
+}
+
+func (s *instanceSet) Instances(tags cloud.InstanceTags) ([]cloud.Instance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out []cloud.Instance
+	for _, inst := range s.instances {
+		if matches(inst.tags, tags) {
+			out = append(out, inst)
+		}
+	}
+	return out, nil
+}
+
+func matches(have, want cloud.InstanceTags) bool {
+	for k, v := range want {
+		if have[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+type instance struct {
+	set     *instanceSet
+	id      string
+	address string
+
+	mu   sync.Mutex
+	tags cloud.InstanceTags
+}
+
+func (i *instance) ID() string      { return i.id }
+func (i *instance) Address() string { return i.address }
+
+func (i *instance) Tags() cloud.InstanceTags {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	out := cloud.InstanceTags{}
+	for k, v := range i.tags {
+		out[k] = v
+	}
+	return out
+}
+
+func (i *instance) SetTags(tags cloud.InstanceTags) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for k, v := range tags {
+		i.tags[k] = v
+	}
+	return nil
+}
+
+func (i *instance) Destroy() error {
+	i.set.mu.Lock()
+	defer i.set.mu.Unlock()
+	delete(i.set.instances, i.id)
+	return nil
+}
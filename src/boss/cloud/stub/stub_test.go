@@ -0,0 +1,88 @@
+package stub
+
+import (
+	"testing"
+
+	"github.com/open-lambda/open-lambda/ol/boss/cloud"
+)
+
+func TestDriverRegistered(t *testing.T) {
+	driver, ok := cloud.Get("stub")
+	if !ok {
+		t.Fatal("stub driver not registered under \"stub\"")
+	}
+	if _, ok := driver.(*Driver); !ok {
+		t.Fatalf("cloud.Get(\"stub\") returned %T, want *Driver", driver)
+	}
+}
+
+func TestInstanceSetCreateAndInstances(t *testing.T) {
+	set, err := (&Driver{}).InstanceSet(nil, cloud.InstanceSetID("set1"), nil)
+	if err != nil {
+		t.Fatalf("InstanceSet: %v", err)
+	}
+
+	tags := cloud.InstanceTags{"ol-instance-set-id": "set1", "ol-worker-id": "worker-1"}
+	inst, err := set.Create("t1", "img1", tags, "./ol worker --detach", "pubkey")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if inst.ID() == "" {
+		t.Fatal("Create returned instance with empty ID")
+	}
+	if inst.Address() == "" {
+		t.Fatal("Create returned instance with empty Address")
+	}
+
+	found, err := set.Instances(cloud.InstanceTags{"ol-instance-set-id": "set1"})
+	if err != nil {
+		t.Fatalf("Instances: %v", err)
+	}
+	if len(found) != 1 || found[0].ID() != inst.ID() {
+		t.Fatalf("Instances returned %v, want just %s", found, inst.ID())
+	}
+
+	// a tag that doesn't match should filter the instance out
+	none, err := set.Instances(cloud.InstanceTags{"ol-worker-id": "worker-2"})
+	if err != nil {
+		t.Fatalf("Instances: %v", err)
+	}
+	if len(none) != 0 {
+		t.Fatalf("Instances with non-matching tag returned %v, want none", none)
+	}
+}
+
+func TestInstanceSetTagsAndDestroy(t *testing.T) {
+	set, err := (&Driver{}).InstanceSet(nil, cloud.InstanceSetID("set1"), nil)
+	if err != nil {
+		t.Fatalf("InstanceSet: %v", err)
+	}
+
+	inst, err := set.Create("t1", "img1", cloud.InstanceTags{"a": "1"}, "", "")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if err := inst.SetTags(cloud.InstanceTags{"b": "2"}); err != nil {
+		t.Fatalf("SetTags: %v", err)
+	}
+	tags := inst.Tags()
+	if tags["a"] != "1" || tags["b"] != "2" {
+		t.Fatalf("Tags() = %v, want a=1 b=2 (SetTags should merge, not replace)", tags)
+	}
+
+	if err := inst.Destroy(); err != nil {
+		t.Fatalf("Destroy: %v", err)
+	}
+	if err := inst.Destroy(); err != nil {
+		t.Fatalf("Destroy should be safe to call twice, got: %v", err)
+	}
+
+	remaining, err := set.Instances(cloud.InstanceTags{"a": "1"})
+	if err != nil {
+		t.Fatalf("Instances: %v", err)
+	}
+	if len(remaining) != 0 {
+		t.Fatalf("Instances after Destroy returned %v, want none", remaining)
+	}
+}
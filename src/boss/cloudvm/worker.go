@@ -1,16 +1,26 @@
 package boss
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
-	"os/exec"
-	"os/user"
+	"os/signal"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
+	"syscall"
 	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/open-lambda/open-lambda/ol/boss/cloud"
+	"github.com/open-lambda/open-lambda/ol/boss/loadbalancer"
+
+	_ "github.com/open-lambda/open-lambda/ol/boss/cloud/stub"
 )
 
 type WorkerState int
@@ -24,65 +34,97 @@ const (
 
 var (
 	clusterLogFile *os.File
-	taskLogFile    *os.File
-	clusterLog     *log.Logger
-	taskLog        *log.Logger
+	clusterLog     *logrus.Logger // structured JSON log of worker state transitions
+	instanceLog    *log.Logger    // plain log handed to cloud.Driver.InstanceSet
 	totalTask      int32
-	sumLatency     int64
-	nLatency       int64
 )
 
 type WorkerPool struct {
-	nextId  int
-	target  int
-	workers []map[string]*Worker
-	queue   chan *Worker
-	WorkerPoolPlatform
+	nextId       int
+	target       int
+	workers      []map[string]*Worker
+	queue        chan *Worker
+	instances    cloud.InstanceSet
+	missingSince map[string]time.Time
+	scheduler    *Scheduler
 	Scaling
 	sync.Mutex
-}
 
-//platform specific attributes and functions
-type WorkerPoolPlatform interface {
-	NewWorker(nextId int) *Worker  //return new worker struct
-	CreateInstance(worker *Worker) //create new instance in the cloud platform
-	DeleteInstance(worker *Worker) //delete cloud platform instance associated with give worker struct
+	// ctx/cancel/wg back graceful shutdown: cancel stops the background
+	// syncLoop/probeLoop goroutines, and wg is Add(1)/Done() by every
+	// goroutine spawned to service a worker or an invocation, so Close
+	// can wait for them all to finish before the process exits.
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
 }
 
 type Worker struct {
-	workerId string
-	workerIp string
-	numTask  int32
-	WorkerPlatform
-	pool  *WorkerPool
-	state WorkerState //state as enum
+	workerId      string
+	workerIp      string
+	numTask       int32
+	instance      cloud.Instance
+	pool          *WorkerPool
+	state         WorkerState //state as enum
+	probeFailures int32
+	idleBehavior  IdleBehavior
+
+	// lastBusyUnixNano is the UnixNano timestamp this worker last
+	// finished serving an invocation, 0 if it never has. It's read out
+	// as InstanceView.LastBusy for GET /instances.json. A plain int64
+	// read/written with atomic so /instances.json doesn't need pool's
+	// lock.
+	lastBusyUnixNano int64
 }
 
-type WorkerPlatform interface {
-	//platform specific attributes and functions
-	//do not require any functions yet
-}
+const (
+	// probeInterval is how often we poll a worker's /status endpoint,
+	// both while waiting for it to come up and while it sits in the
+	// ready queue.
+	probeInterval = 2 * time.Second
+
+	// maxProbeFailures is how many consecutive failed probes a RUNNING
+	// worker tolerates before we pull it out of the ready queue.
+	maxProbeFailures = 3
+
+	// syncInterval is how often we reconcile pool.workers against the
+	// cloud provider's own view of which instances exist.
+	syncInterval = 30 * time.Second
+
+	// destroyGraceDelay is how long a worker stays in the DESTROYING
+	// bucket, after the provider first stopped reporting it, before we
+	// forget about it entirely.
+	destroyGraceDelay = 3 * syncInterval
+)
 
 func NewWorkerPool() *WorkerPool {
 	clusterLogFile, _ = os.Create("cluster.log")
-	taskLogFile, _ = os.Create("tasks.log")
-	clusterLog = log.New(clusterLogFile, "", 0)
-	taskLog = log.New(taskLogFile, "", 0)
-	clusterLog.SetFlags(log.Lmicroseconds)
-	taskLog.SetFlags(log.Lmicroseconds)
-
-	var pool *WorkerPool
-	if Conf.Platform == "gcp" {
-		pool = NewGcpWorkerPool()
-	} else if Conf.Platform == "azure" {
-		pool = NewAzureWorkerPool()
-		conf, err = ReadAzureConfig()
-	} else if Conf.Platform == "DO" {
-		pool = NewDOWorkerPool()
-	} else if Conf.Platform == "mock" {
-		pool = NewMockWorkerPool()
+	instanceLog = log.New(clusterLogFile, "", log.Lmicroseconds)
+	clusterLog = logrus.New()
+	clusterLog.SetOutput(clusterLogFile)
+	clusterLog.SetFormatter(&logrus.JSONFormatter{})
+
+	driver, ok := cloud.Get(Conf.Platform)
+	if !ok {
+		panic(fmt.Sprintf("no cloud driver registered for platform %q", Conf.Platform))
 	}
 
+	instances, err := driver.InstanceSet(Conf.PlatformConfig, instanceSetID, instanceLog)
+	if err != nil {
+		panic(fmt.Sprintf("failed to create InstanceSet for platform %q: %v", Conf.Platform, err))
+	}
+
+	lbStrategy := Conf.LbStrategy
+	if lbStrategy == "" {
+		lbStrategy = "random"
+	}
+	if _, err := loadbalancer.InitLoadBalancer(lbStrategy, Conf.LbConfig); err != nil {
+		panic(fmt.Sprintf("failed to init load balancer strategy %q: %v", lbStrategy, err))
+	}
+
+	pool := &WorkerPool{instances: instances, missingSince: map[string]time.Time{}}
+	pool.ctx, pool.cancel = context.WithCancel(context.Background())
+	pool.scheduler = NewScheduler(pool)
 	pool.nextId = 1
 	pool.workers = []map[string]*Worker{
 		make(map[string]*Worker), //starting
@@ -99,21 +141,26 @@ func NewWorkerPool() *WorkerPool {
 
 	log.Printf("READY: worker pool of type %s", Conf.Platform)
 
-	//log total outstanding tasks
-	go func() {
-		for true {
-			time.Sleep(time.Second)
-			var avgLatency int64 = 0
-			if nLatency > 0 {
-				avgLatency = sumLatency / nLatency
-			}
-			taskLog.Printf("tasks=%d, average_latency(ms)=%d", totalTask, avgLatency)
-		}
-	}()
+	go pool.syncLoop()
+	go pool.probeLoop()
+	pool.installSignalHandler()
 
 	return pool
 }
 
+// installSignalHandler calls Close on SIGINT/SIGTERM, so an operator
+// killing the boss process (or a container runtime stopping it) tears down
+// the cluster gracefully instead of leaking cloud instances.
+func (pool *WorkerPool) installSignalHandler() {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		sig := <-sigs
+		log.Printf("received %s, shutting down worker pool gracefully\n", sig)
+		pool.Close()
+	}()
+}
+
 //return number of workers in the pool
 func (pool *WorkerPool) Size() int {
 	pool.Lock()
@@ -125,15 +172,48 @@ func (pool *WorkerPool) Size() int {
 	return size
 }
 
+// countTowardTarget is like Size, but excludes workers an operator has put
+// into hold or drain: those don't count toward target, so updateCluster
+// launches replacements for them while they're set aside.
+func (pool *WorkerPool) countTowardTarget() int {
+	pool.Lock()
+	defer pool.Unlock()
+	count := 0
+	for i := 0; i < len(pool.workers); i++ {
+		for _, worker := range pool.workers[i] {
+			if worker.idleBehavior == IdleHold || worker.idleBehavior == IdleDrain {
+				continue
+			}
+			count++
+		}
+	}
+	return count
+}
+
+// logTransition records a worker's state transition to clusterLog as a
+// structured JSON entry (worker_id, state, target, and the current size of
+// every bucket), replacing the old fixed-format Printf lines.
+func (pool *WorkerPool) logTransition(workerId, state string) {
+	clusterLog.WithFields(logrus.Fields{
+		"worker_id":  workerId,
+		"state":      state,
+		"target":     pool.target,
+		"starting":   len(pool.workers[STARTING]),
+		"running":    len(pool.workers[RUNNING]),
+		"cleaning":   len(pool.workers[CLEANING]),
+		"destroying": len(pool.workers[DESTROYING]),
+	}).Info("worker state transition")
+}
+
 //renamed Scale() -> SetTarget()
 func (pool *WorkerPool) SetTarget(target int) {
 	pool.Lock()
-	
+
 	pool.target = target
-	clusterLog.Printf("set target=%d", pool.target)
-	
+	clusterLog.WithField("target", pool.target).Info("set target")
+
 	pool.Unlock()
-	
+
 	pool.updateCluster()
 }
 
@@ -144,25 +224,49 @@ func (pool *WorkerPool) startNewWorker() {
 	log.Printf("starting new worker\n")
 	nextId := pool.nextId
 	pool.nextId += 1
-	worker := pool.NewWorker(nextId)
+	worker := &Worker{
+		workerId:     fmt.Sprintf("worker-%d", nextId),
+		pool:         pool,
+		idleBehavior: IdleRun,
+	}
 	worker.state = STARTING
 	pool.workers[STARTING][worker.workerId] = worker
-	clusterLog.Printf("%s: starting [target=%d, starting=%d, running=%d, cleaning=%d, destroying=%d]",
-		worker.workerId, pool.target,
-		len(pool.workers[STARTING]),
-		len(pool.workers[RUNNING]),
-		len(pool.workers[CLEANING]),
-		len(pool.workers[DESTROYING]))
+	pool.logTransition(worker.workerId, "starting")
 
 	pool.Unlock()
 
+	pool.wg.Add(1)
 	go func() { // should be able to create multiple instances simultaneously
-		pool.CreateInstance(worker) //create new instance
+		defer pool.wg.Done()
 
-		if Conf.Platform == "azure" {
-			worker.WorkerPlatform.(*AzureWorker).startWorker()
-		} else {
-			worker.runCmd("./ol worker --detach") // start worker
+		tags := cloud.InstanceTags{
+			tagKeyInstanceSetID: instanceSetID,
+			tagKeyWorkerID:      worker.workerId,
+		}
+		instance, err := pool.instances.Create(Conf.Worker_Instance_Type, Conf.Worker_Image_Id, tags, "./ol worker --detach", Conf.Worker_Public_Key)
+		if err != nil {
+			metricWorkerLaunchesTotal.WithLabelValues("error").Inc()
+			panic(fmt.Sprintf("failed to create instance for %s: %v", worker.workerId, err))
+		}
+		metricWorkerLaunchesTotal.WithLabelValues("success").Inc()
+		worker.instance = instance
+		worker.workerIp = instance.Address()
+
+		// don't call a worker RUNNING until it actually answers a
+		// health check on its /status endpoint -- booting the VM and
+		// having ./ol worker start listening are not the same moment.
+		if !pool.waitUntilHealthy(worker) {
+			log.Printf("%s: pool shutting down before it became healthy, destroying\n", worker.workerId)
+			if err := worker.instance.Destroy(); err != nil {
+				log.Printf("%s: failed to destroy instance: %v\n", worker.workerId, err)
+			} else {
+				metricWorkerDestroysTotal.Inc()
+			}
+
+			pool.Lock()
+			delete(pool.workers[STARTING], worker.workerId)
+			pool.Unlock()
+			return
 		}
 
 		//change state starting -> running
@@ -172,12 +276,7 @@ func (pool *WorkerPool) startNewWorker() {
 		delete(pool.workers[STARTING], worker.workerId)
 		pool.workers[RUNNING][worker.workerId] = worker
 
-		clusterLog.Printf("%s: running [target=%d, starting=%d, running=%d, cleaning=%d, destroying=%d]",
-			worker.workerId, pool.target,
-			len(pool.workers[STARTING]),
-			len(pool.workers[RUNNING]),
-			len(pool.workers[CLEANING]),
-			len(pool.workers[DESTROYING]))
+		pool.logTransition(worker.workerId, "running")
 		pool.queue <- worker
 		log.Printf("%s ready\n", worker.workerId)
 		
@@ -196,12 +295,7 @@ func (pool *WorkerPool) recoverWorker(worker *Worker) {
 	delete(pool.workers[CLEANING], worker.workerId)
 	pool.workers[RUNNING][worker.workerId] = worker
 
-	clusterLog.Printf("%s: running [target=%d, starting=%d, running=%d, cleaning=%d, destroying=%d]",
-		worker.workerId, pool.target,
-		len(pool.workers[STARTING]),
-		len(pool.workers[RUNNING]),
-		len(pool.workers[CLEANING]),
-		len(pool.workers[DESTROYING]))
+	pool.logTransition(worker.workerId, "running")
 	
 	pool.Unlock()
 
@@ -217,16 +311,14 @@ func (pool *WorkerPool) cleanWorker(worker *Worker) {
 	delete(pool.workers[RUNNING], worker.workerId)
 	pool.workers[CLEANING][worker.workerId] = worker
 
-	clusterLog.Printf("%s: cleaning [target=%d, starting=%d, running=%d, cleaning=%d, destroying=%d]",
-		worker.workerId, pool.target,
-		len(pool.workers[STARTING]),
-		len(pool.workers[RUNNING]),
-		len(pool.workers[CLEANING]),
-		len(pool.workers[DESTROYING]))
+	pool.logTransition(worker.workerId, "cleaning")
 	
 	pool.Unlock()
 
+	pool.wg.Add(1)
 	go func(worker *Worker) {
+		defer pool.wg.Done()
+
 		for worker.numTask > 0 { //wait until all task is completed
 			fmt.Printf("%s cleaning: %d", worker.workerId, worker.numTask)
 			pool.Lock()
@@ -249,17 +341,19 @@ func (pool *WorkerPool) detroyWorker(worker *Worker) {
 	delete(pool.workers[CLEANING], worker.workerId)
 	pool.workers[DESTROYING][worker.workerId] = worker
 
-	clusterLog.Printf("%s: destroying [target=%d, starting=%d, running=%d, cleaning=%d, destroying=%d]",
-		worker.workerId, pool.target,
-		len(pool.workers[STARTING]),
-		len(pool.workers[RUNNING]),
-		len(pool.workers[CLEANING]),
-		len(pool.workers[DESTROYING]))
+	pool.logTransition(worker.workerId, "destroying")
 
 	pool.Unlock()
 
+	pool.wg.Add(1)
 	go func() { // should be able to destroy multiple instances simultaneously
-		pool.DeleteInstance(worker) //delete new instance
+		defer pool.wg.Done()
+
+		if err := worker.instance.Destroy(); err != nil {
+			log.Printf("%s: failed to destroy instance: %v\n", worker.workerId, err)
+		} else {
+			metricWorkerDestroysTotal.Inc()
+		}
 
 		// remove from cluster
 		pool.Lock()
@@ -267,12 +361,7 @@ func (pool *WorkerPool) detroyWorker(worker *Worker) {
 		delete(pool.workers[DESTROYING], worker.workerId)
 
 		log.Printf("%s destroyed\n", worker.workerId)
-		clusterLog.Printf("%s: destroyed [target=%d, starting=%d, running=%d, cleaning=%d, destroying=%d]",
-			worker.workerId, pool.target,
-			len(pool.workers[STARTING]),
-			len(pool.workers[RUNNING]),
-			len(pool.workers[CLEANING]),
-			len(pool.workers[DESTROYING]))
+		pool.logTransition(worker.workerId, "destroyed")
 		pool.Unlock()
 		
 		pool.updateCluster()
@@ -281,7 +370,9 @@ func (pool *WorkerPool) detroyWorker(worker *Worker) {
 
 // called when worker is been evicted from cleaning or destroying map
 func (pool *WorkerPool) updateCluster() {
-	scaleSize := pool.target - pool.Size() // scaleSize = target - size of cluster
+	defer pool.reportClusterSizes()
+
+	scaleSize := pool.target - pool.countTowardTarget() // scaleSize = target - size of cluster
 
 	if scaleSize > 0 {
 		for i := 0; i < scaleSize; i++ {
@@ -327,6 +418,9 @@ func (pool *WorkerPool) updateCluster() {
 
 //run lambda function
 func (pool *WorkerPool) RunLambda(w http.ResponseWriter, r *http.Request) {
+	pool.wg.Add(1)
+	defer pool.wg.Done()
+
 	starttime := time.Now()
 	if len(pool.workers[STARTING])+len(pool.workers[RUNNING]) == 0 {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -339,37 +433,91 @@ func (pool *WorkerPool) RunLambda(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	worker := <-pool.queue
-	pool.queue <- worker
-	atomic.AddInt32(&worker.numTask, 1)
-	atomic.AddInt32(&totalTask, 1)
-	if Conf.Scaling == "auto" {
-		pool.Scale(pool)
+	priority := 0
+	if p := r.Header.Get("X-OL-Priority"); p != "" {
+		if parsed, err := strconv.Atoi(p); err == nil {
+			priority = parsed
+		}
 	}
 
-	if Conf.Platform == "mock" {
-		s := fmt.Sprintf("hello from %s\n", worker.workerId)
-		w.WriteHeader(http.StatusOK)
-		_, err := w.Write([]byte(s))
-		if err != nil {
-			panic(err)
+	memoryMB := 0
+	if m := r.Header.Get("X-OL-Memory-MB"); m != "" {
+		if parsed, err := strconv.Atoi(m); err == nil {
+			memoryMB = parsed
 		}
-	} else {
-		forwardTask(w, r, worker.workerIp)
 	}
-	atomic.AddInt32(&worker.numTask, -1)
+
+	var packages []string
+	if p := r.Header.Get("X-OL-Packages"); p != "" {
+		packages = strings.Split(p, ",")
+	}
+
+	req := &InvocationRequest{
+		LambdaName:      lambdaNameFromRequest(r),
+		Priority:        priority,
+		SubmitTime:      starttime,
+		Packages:        packages,
+		MemoryMB:        memoryMB,
+		RuntimeKind:     r.Header.Get("X-OL-Runtime"),
+		Language:        r.Header.Get("X-OL-Language"),
+		BaseImageDigest: r.Header.Get("X-OL-Base-Image-Digest"),
+		w:               w,
+		r:               r,
+	}
+
+	atomic.AddInt32(&totalTask, 1)
+	admitted := pool.scheduler.Submit(req)
 	atomic.AddInt32(&totalTask, -1)
 
-	latency := time.Since(starttime).Milliseconds()
+	if admitted && Conf.Scaling == "auto" {
+		pool.Scale(pool)
+	}
+
+	latency := time.Since(starttime)
 
-	atomic.AddInt64(&sumLatency, latency)
-	atomic.AddInt64(&nLatency, 1)
+	status := "ok"
+	if !admitted {
+		status = "rejected"
+	}
+	metricInvocationsTotal.WithLabelValues(req.LambdaName, status).Inc()
+	if admitted {
+		metricInvocationLatency.WithLabelValues(req.LambdaName).Observe(latency.Seconds())
+	}
 }
 
 //force kill workers
 func (pool *WorkerPool) Close() {
 	log.Println("closing worker pool")
 	pool.SetTarget(0)
+
+	// wait for every worker we just told to shut down to actually reach
+	// DESTROYED, so we don't cancel the context (and stop the
+	// background loops) while a DeleteInstance call is still in flight.
+	//
+	// STARTING is deliberately NOT included here: a STARTING worker only
+	// stops booting once the context is canceled (waitUntilHealthy reacts
+	// to pool.ctx.Done()), so waiting for it here first would deadlock.
+	// Canceling the context below is what makes a still-booting worker's
+	// startNewWorker goroutine destroy its half-created instance and drop
+	// its STARTING entry; pool.wg.Wait() blocks until that's done.
+	for {
+		pool.Lock()
+		remaining := len(pool.workers[CLEANING]) + len(pool.workers[DESTROYING])
+		pool.Unlock()
+		if remaining == 0 {
+			break
+		}
+		time.Sleep(time.Second)
+	}
+
+	pool.cancel()
+	pool.wg.Wait()
+
+	if clusterLogFile != nil {
+		clusterLogFile.Close()
+	}
+
+	log.Println("worker pool closed")
 }
 
 // forward request to worker
@@ -393,37 +541,6 @@ func forwardTask(w http.ResponseWriter, req *http.Request, workerIp string) erro
 	return nil
 }
 
-// ssh to worker and run command
-func (w *Worker) runCmd(command string) {
-	cwd, err := os.Getwd()
-	if err != nil {
-		panic(err)
-	}
-
-	user, err := user.Current()
-	if err != nil {
-		panic(err)
-	}
-
-	cmd := fmt.Sprintf("cd %s; %s", cwd, command)
-
-	tries := 10
-	for tries > 0 {
-		sshcmd := exec.Command("ssh", user.Username+"@"+w.workerIp, "-o", "StrictHostKeyChecking=no", "-C", cmd)
-		stdoutStderr, err := sshcmd.CombinedOutput()
-		log.Printf("%s\n", stdoutStderr)
-		if err == nil {
-			break
-		}
-		tries -= 1
-		if tries == 0 {
-			log.Println(sshcmd.String())
-			panic(err)
-		}
-		time.Sleep(5 * time.Second)
-	}
-}
-
 //return wokers' id and number of tasks
 func (pool *WorkerPool) StatusTasks() map[string]int {
 	var output = map[string]int{}
@@ -459,3 +576,186 @@ func (pool *WorkerPool) StatusCluster() map[string]int {
 
 	return output
 }
+
+// probe performs a single health check against a worker's /status endpoint.
+func (pool *WorkerPool) probe(worker *Worker) bool {
+	client := http.Client{Timeout: probeInterval}
+	resp, err := client.Get(fmt.Sprintf("http://%s:5000/status", worker.workerIp))
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
+
+// waitUntilHealthy blocks until worker answers a health check, or until
+// pool.ctx is canceled (Close() was called while we were still waiting). It
+// returns false in the canceled case, so Close() can't hang forever behind
+// a VM that never comes up.
+func (pool *WorkerPool) waitUntilHealthy(worker *Worker) bool {
+	for !pool.probe(worker) {
+		select {
+		case <-pool.ctx.Done():
+			return false
+		case <-time.After(probeInterval):
+		}
+	}
+	return true
+}
+
+// probeLoop re-checks every worker sitting in the ready queue and evicts
+// anyone that's failed maxProbeFailures health checks in a row, so a
+// partially-wedged worker stops receiving new invocations.
+func (pool *WorkerPool) probeLoop() {
+	for {
+		select {
+		case <-pool.ctx.Done():
+			return
+		case <-time.After(probeInterval):
+			pool.reprobeQueue()
+		}
+	}
+}
+
+func (pool *WorkerPool) reprobeQueue() {
+	n := len(pool.queue)
+	for i := 0; i < n; i++ {
+		var worker *Worker
+		select {
+		case worker = <-pool.queue:
+		default:
+			return
+		}
+
+		if pool.probe(worker) {
+			atomic.StoreInt32(&worker.probeFailures, 0)
+			pool.queue <- worker
+			continue
+		}
+
+		failures := atomic.AddInt32(&worker.probeFailures, 1)
+		if failures >= maxProbeFailures {
+			clusterLog.WithFields(logrus.Fields{
+				"worker_id": worker.workerId,
+				"failures":  failures,
+			}).Warn("evicting worker from queue after consecutive failed probes")
+			pool.cleanWorker(worker)
+			continue
+		}
+		pool.queue <- worker
+	}
+}
+
+// syncLoop periodically reconciles pool.workers against the cloud
+// provider's own view of which instances exist, so that a boss restart (or
+// an instance destroyed out-of-band) doesn't leave the queue handing out
+// workers we've lost track of.
+func (pool *WorkerPool) syncLoop() {
+	for {
+		select {
+		case <-pool.ctx.Done():
+			return
+		case <-time.After(syncInterval):
+			pool.sync()
+		}
+	}
+}
+
+func (pool *WorkerPool) sync() {
+	instances, err := pool.instances.Instances(cloud.InstanceTags{tagKeyInstanceSetID: instanceSetID})
+	if err != nil {
+		log.Printf("sync: failed to list instances: %v\n", err)
+		return
+	}
+
+	seen := map[string]bool{}
+
+	pool.Lock()
+
+	for _, inst := range instances {
+		workerId := inst.Tags()[tagKeyWorkerID]
+		if workerId == "" {
+			continue // not yet tagged as belonging to a worker
+		}
+		seen[workerId] = true
+		delete(pool.missingSince, workerId)
+
+		if pool.findWorker(workerId) != nil {
+			continue // already tracked
+		}
+
+		// the provider says this instance is ours, but we have no
+		// record of it (e.g. the boss just restarted): adopt it,
+		// recovering whatever idle behavior it was last tagged with.
+		idleBehavior, err := parseIdleBehavior(inst.Tags()[tagKeyIdleBehavior])
+		if err != nil {
+			idleBehavior = IdleRun
+		}
+		worker := &Worker{
+			workerId:     workerId,
+			workerIp:     inst.Address(),
+			instance:     inst,
+			pool:         pool,
+			state:        RUNNING,
+			idleBehavior: idleBehavior,
+		}
+		pool.workers[RUNNING][workerId] = worker
+		clusterLog.WithFields(logrus.Fields{
+			"worker_id":     workerId,
+			"idle_behavior": idleBehavior,
+		}).Info("adopted untracked instance")
+
+		if idleBehavior == IdleDrain {
+			pool.wg.Add(1)
+			go func(worker *Worker) {
+				defer pool.wg.Done()
+				pool.drainWorker(worker)
+			}(worker)
+		}
+		if idleBehavior != IdleRun {
+			continue // hold/drain workers never rejoin the ready queue
+		}
+
+		pool.queue <- worker
+	}
+
+	for _, state := range []WorkerState{STARTING, RUNNING, CLEANING} {
+		for workerId, worker := range pool.workers[state] {
+			if seen[workerId] {
+				continue
+			}
+			delete(pool.workers[state], workerId)
+			worker.state = DESTROYING
+			pool.workers[DESTROYING][workerId] = worker
+			pool.missingSince[workerId] = time.Now()
+			clusterLog.WithField("worker_id", workerId).Warn("missing from provider, marking destroyed")
+		}
+	}
+
+	for workerId := range pool.workers[DESTROYING] {
+		if seen[workerId] {
+			continue
+		}
+		since, ok := pool.missingSince[workerId]
+		if ok && time.Since(since) > destroyGraceDelay {
+			delete(pool.workers[DESTROYING], workerId)
+			delete(pool.missingSince, workerId)
+			clusterLog.WithField("worker_id", workerId).Info("forgotten after grace period")
+		}
+	}
+
+	pool.Unlock()
+
+	pool.updateCluster()
+}
+
+// findWorker looks up a worker by ID across all four state buckets. Caller
+// must hold pool.Lock.
+func (pool *WorkerPool) findWorker(workerId string) *Worker {
+	for i := 0; i < len(pool.workers); i++ {
+		if worker, ok := pool.workers[i][workerId]; ok {
+			return worker
+		}
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package boss
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/open-lambda/open-lambda/ol/boss/cloud"
+	"github.com/open-lambda/open-lambda/ol/boss/cloud/stub"
+)
+
+// newTestPool builds a WorkerPool the way NewWorkerPool does, minus the
+// side effects (log files, signal handlers, background loops) that would
+// make it awkward to drive from a test. Every sub-test is responsible for
+// calling whichever of syncLoop/probeLoop/sync/etc it actually wants to
+// exercise.
+func newTestPool(t *testing.T) (*WorkerPool, cloud.InstanceSet) {
+	t.Helper()
+
+	Conf = &Config{
+		Worker_Cap:              8,
+		Default_Max_Concurrency: 8,
+		Queue_Max_Depth:         8,
+	}
+
+	clusterLog = logrus.New()
+	clusterLog.SetOutput(io.Discard)
+
+	instances, err := (&stub.Driver{}).InstanceSet(nil, cloud.InstanceSetID(instanceSetID), nil)
+	if err != nil {
+		t.Fatalf("InstanceSet: %v", err)
+	}
+
+	pool := &WorkerPool{instances: instances, missingSince: map[string]time.Time{}}
+	pool.ctx, pool.cancel = context.WithCancel(context.Background())
+	pool.nextId = 1
+	pool.workers = []map[string]*Worker{
+		make(map[string]*Worker),
+		make(map[string]*Worker),
+		make(map[string]*Worker),
+		make(map[string]*Worker),
+	}
+	pool.queue = make(chan *Worker, Conf.Worker_Cap)
+	pool.scheduler = NewScheduler(pool)
+
+	return pool, instances
+}
+
+// TestSchedulerAdmissionControl checks that Submit rejects with 429 once the
+// pending queue is at Conf.Queue_Max_Depth, rather than blocking forever for
+// a worker that will never arrive.
+func TestSchedulerAdmissionControl(t *testing.T) {
+	pool, _ := newTestPool(t)
+	Conf.Queue_Max_Depth = 0
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/run/my-lambda", nil)
+	req := &InvocationRequest{LambdaName: "my-lambda", SubmitTime: time.Now(), w: w, r: r}
+
+	if admitted := pool.scheduler.Submit(req); admitted {
+		t.Fatal("Submit admitted a request with Queue_Max_Depth=0, want rejected")
+	}
+	if w.Code != 429 {
+		t.Fatalf("status = %d, want 429", w.Code)
+	}
+}
+
+// TestSyncAdoptsUntrackedInstance checks that sync() adopts a provider-side
+// instance the pool has no record of (e.g. after a boss restart) into the
+// RUNNING bucket and the ready queue.
+func TestSyncAdoptsUntrackedInstance(t *testing.T) {
+	pool, instances := newTestPool(t)
+	pool.target = 1 // so updateCluster's scale-down pass doesn't immediately clean up the adoptee
+
+	tags := cloud.InstanceTags{tagKeyInstanceSetID: instanceSetID, tagKeyWorkerID: "worker-1"}
+	if _, err := instances.Create("t1", "img1", tags, "", ""); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	pool.sync()
+
+	pool.Lock()
+	worker, ok := pool.workers[RUNNING]["worker-1"]
+	pool.Unlock()
+	if !ok {
+		t.Fatal("sync did not adopt the untracked instance into RUNNING")
+	}
+	if worker.idleBehavior != IdleRun {
+		t.Fatalf("adopted worker idleBehavior = %q, want %q", worker.idleBehavior, IdleRun)
+	}
+
+	select {
+	case queued := <-pool.queue:
+		if queued != worker {
+			t.Fatal("sync queued a different worker than the one it adopted")
+		}
+	default:
+		t.Fatal("sync adopted the worker but never put it in the ready queue")
+	}
+}
+
+// TestSyncMarksMissingThenForgets checks that sync() moves a tracked worker
+// to DESTROYING once the provider stops reporting its instance, and forgets
+// it entirely once destroyGraceDelay has passed.
+func TestSyncMarksMissingThenForgets(t *testing.T) {
+	pool, _ := newTestPool(t)
+
+	worker := &Worker{workerId: "worker-missing", pool: pool, state: RUNNING}
+	pool.workers[RUNNING]["worker-missing"] = worker
+
+	pool.sync()
+
+	pool.Lock()
+	_, stillRunning := pool.workers[RUNNING]["worker-missing"]
+	destroying, isDestroying := pool.workers[DESTROYING]["worker-missing"]
+	since, hasMissingSince := pool.missingSince["worker-missing"]
+	pool.Unlock()
+
+	if stillRunning {
+		t.Fatal("sync left an unreported worker in RUNNING")
+	}
+	if !isDestroying || destroying != worker {
+		t.Fatal("sync did not move the unreported worker to DESTROYING")
+	}
+	if !hasMissingSince {
+		t.Fatal("sync did not record missingSince for the unreported worker")
+	}
+
+	// back-date missingSince past destroyGraceDelay and sync again; the
+	// worker should now be forgotten entirely.
+	pool.Lock()
+	pool.missingSince["worker-missing"] = since.Add(-destroyGraceDelay - time.Second)
+	pool.Unlock()
+
+	pool.sync()
+
+	pool.Lock()
+	_, stillDestroying := pool.workers[DESTROYING]["worker-missing"]
+	_, stillHasMissingSince := pool.missingSince["worker-missing"]
+	pool.Unlock()
+
+	if stillDestroying {
+		t.Fatal("sync did not forget the worker after destroyGraceDelay")
+	}
+	if stillHasMissingSince {
+		t.Fatal("sync left a stale missingSince entry after forgetting the worker")
+	}
+}
+
+// TestCloseDrainsBeforeCancel checks that Close() waits for a worker that's
+// mid-cleanup (CLEANING -> DESTROYING -> gone) to actually finish being
+// destroyed before it cancels pool.ctx and returns, instead of racing the
+// in-flight Destroy() call.
+func TestCloseDrainsBeforeCancel(t *testing.T) {
+	pool, instances := newTestPool(t)
+
+	inst, err := instances.Create("t1", "img1", cloud.InstanceTags{tagKeyWorkerID: "worker-1"}, "", "")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	worker := &Worker{workerId: "worker-1", pool: pool, state: RUNNING, instance: inst}
+	pool.workers[RUNNING]["worker-1"] = worker
+
+	pool.cleanWorker(worker)
+
+	done := make(chan struct{})
+	go func() {
+		pool.Close()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Close() did not return")
+	}
+
+	if pool.Size() != 0 {
+		t.Fatalf("pool.Size() = %d after Close, want 0", pool.Size())
+	}
+	if err := pool.ctx.Err(); err == nil {
+		t.Fatal("Close() returned without canceling pool.ctx")
+	}
+}
+
+// TestIdleRunDoesNotDoubleQueue checks that setting IdleRun on a worker
+// already sitting in the ready queue doesn't push a second copy of it,
+// guarding against the double-enqueue idle.go's queueContains exists to
+// prevent.
+func TestIdleRunDoesNotDoubleQueue(t *testing.T) {
+	pool, _ := newTestPool(t)
+
+	worker := &Worker{workerId: "worker-1", pool: pool, state: RUNNING, idleBehavior: IdleRun}
+	pool.workers[RUNNING]["worker-1"] = worker
+	pool.queue <- worker
+	pool.target = 1 // matches the one RUNNING worker, so updateCluster is a no-op below
+
+	pool.setIdleBehavior(worker, IdleRun)
+
+	if n := len(pool.queue); n != 1 {
+		t.Fatalf("len(pool.queue) = %d after redundant IdleRun, want 1", n)
+	}
+}
@@ -0,0 +1,74 @@
+package boss
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Config is the boss's config file, parsed by LoadConf at startup. The
+// on-disk format is JSON, matching how the rest of open-lambda is
+// configured.
+type Config struct {
+	// Platform names the cloud.Driver to use ("gcp", "azure", "do",
+	// "stub", ...). "stub" is the in-memory driver used for unit tests
+	// and local development without cloud credentials.
+	Platform string `json:"platform"`
+
+	// PlatformConfig is passed through unparsed to that Driver's
+	// InstanceSet constructor.
+	PlatformConfig map[string]interface{} `json:"platform_config"`
+
+	Worker_Instance_Type string `json:"worker_instance_type"`
+	Worker_Image_Id      string `json:"worker_image_id"`
+	Worker_Public_Key    string `json:"worker_public_key"`
+
+	// Worker_Cap bounds how many workers can ever sit in the ready
+	// queue at once.
+	Worker_Cap int `json:"worker_cap"`
+
+	// Scaling selects the auto-scaling policy: "auto" runs
+	// ScalingThreshold, "manual" leaves pool.target alone between
+	// explicit SetTarget calls.
+	Scaling string `json:"scaling"`
+
+	// Lambda_Limits caps per-lambda concurrency by name; lambdas not
+	// listed fall back to Default_Max_Concurrency.
+	Lambda_Limits           map[string]int `json:"lambda_limits"`
+	Default_Max_Concurrency int            `json:"default_max_concurrency"`
+
+	// Queue_Max_Depth is the admission-control cutoff: Scheduler.Submit
+	// rejects with 429 once the pending queue reaches this depth.
+	Queue_Max_Depth int `json:"queue_max_depth"`
+
+	// LbStrategy names the loadbalancer.Register'd strategy to install
+	// at startup (e.g. "random", "kmeans", "kmodes", "rendezvous");
+	// defaults to "random" if empty. LbConfig is passed through
+	// unparsed to that strategy's factory.
+	LbStrategy string          `json:"lb_strategy"`
+	LbConfig   json.RawMessage `json:"lb_config"`
+}
+
+// Conf is the boss's active configuration, set by LoadConf before
+// NewWorkerPool is called.
+var Conf *Config
+
+// LoadConf reads and parses the boss config file at path into Conf.
+func LoadConf(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read boss config %q: %w", path, err)
+	}
+
+	conf := &Config{
+		Default_Max_Concurrency: 10,
+		Queue_Max_Depth:         1024,
+		Worker_Cap:              64,
+	}
+	if err := json.Unmarshal(data, conf); err != nil {
+		return fmt.Errorf("failed to parse boss config %q: %w", path, err)
+	}
+
+	Conf = conf
+	return nil
+}
@@ -0,0 +1,151 @@
+package boss
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/open-lambda/open-lambda/ol/boss/cloud"
+)
+
+// IdleBehavior controls what a RUNNING worker does when it isn't needed to
+// meet pool.target. "run" is the default: the worker sits in the ready
+// queue like any other. "hold" keeps the instance booted but out of the
+// queue, so an operator can attach and debug it without it racing new
+// invocations. "drain" refuses new invocations and transitions to CLEANING
+// once it finishes whatever it's already running.
+type IdleBehavior string
+
+const (
+	IdleRun   IdleBehavior = "run"
+	IdleHold  IdleBehavior = "hold"
+	IdleDrain IdleBehavior = "drain"
+)
+
+// tagKeyIdleBehavior persists a worker's IdleBehavior on its cloud instance,
+// so the state survives a boss restart and is recovered by WorkerPool.sync.
+const tagKeyIdleBehavior = "ol-idle-behavior"
+
+func parseIdleBehavior(s string) (IdleBehavior, error) {
+	switch IdleBehavior(s) {
+	case IdleRun, IdleHold, IdleDrain:
+		return IdleBehavior(s), nil
+	default:
+		return "", fmt.Errorf("invalid idle behavior %q", s)
+	}
+}
+
+// SetIdleBehavior implements POST /workers/{id}/idle_behavior with a JSON
+// body of the form {"idle_behavior": "hold"}.
+func (pool *WorkerPool) SetIdleBehavior(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/workers/"), "/idle_behavior")
+
+	var body struct {
+		IdleBehavior string `json:"idle_behavior"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	behavior, err := parseIdleBehavior(body.IdleBehavior)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	pool.Lock()
+	worker := pool.findWorker(id)
+	pool.Unlock()
+	if worker == nil {
+		http.Error(w, fmt.Sprintf("no such worker %q", id), http.StatusNotFound)
+		return
+	}
+
+	pool.setIdleBehavior(worker, behavior)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (pool *WorkerPool) setIdleBehavior(worker *Worker, behavior IdleBehavior) {
+	pool.Lock()
+	worker.idleBehavior = behavior
+	pool.Unlock()
+
+	if worker.instance != nil {
+		tags := cloud.InstanceTags{tagKeyIdleBehavior: string(behavior)}
+		if err := worker.instance.SetTags(tags); err != nil {
+			log.Printf("%s: failed to persist idle behavior tag: %v\n", worker.workerId, err)
+		}
+	}
+
+	clusterLog.WithFields(logrus.Fields{
+		"worker_id":     worker.workerId,
+		"idle_behavior": behavior,
+	}).Info("set idle behavior")
+
+	switch behavior {
+	case IdleHold:
+		pool.removeFromQueue(worker)
+	case IdleDrain:
+		pool.removeFromQueue(worker)
+		pool.wg.Add(1)
+		go func() {
+			defer pool.wg.Done()
+			pool.drainWorker(worker)
+		}()
+	case IdleRun:
+		pool.Lock()
+		running := worker.state == RUNNING
+		pool.Unlock()
+		if running && !pool.queueContains(worker) {
+			pool.queue <- worker
+		}
+	}
+
+	pool.updateCluster()
+}
+
+// removeFromQueue pulls target out of the ready queue, if it's there,
+// without disturbing the order of any other worker.
+func (pool *WorkerPool) removeFromQueue(target *Worker) {
+	n := len(pool.queue)
+	for i := 0; i < n; i++ {
+		worker := <-pool.queue
+		if worker == target {
+			continue
+		}
+		pool.queue <- worker
+	}
+}
+
+// queueContains drains pool.queue and refills it, reporting whether target
+// was present. setIdleBehavior uses this to avoid pushing a second copy of
+// the same worker into the queue when "run" is set on a worker that's
+// already sitting in it (e.g. a retried idle_behavior call).
+func (pool *WorkerPool) queueContains(target *Worker) bool {
+	n := len(pool.queue)
+	found := false
+	for i := 0; i < n; i++ {
+		worker := <-pool.queue
+		if worker == target {
+			found = true
+		}
+		pool.queue <- worker
+	}
+	return found
+}
+
+// drainWorker waits for a drained worker to finish its in-flight
+// invocations, then cleans it up like any other worker being scaled down.
+func (pool *WorkerPool) drainWorker(worker *Worker) {
+	for atomic.LoadInt32(&worker.numTask) > 0 {
+		time.Sleep(time.Second)
+	}
+	pool.cleanWorker(worker)
+}
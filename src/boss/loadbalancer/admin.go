@@ -0,0 +1,59 @@
+package loadbalancer
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// StatusHandler implements GET /status/lb, dumping the active strategy's
+// live internal state (centroids for KMeans, mode vectors for KModes,
+// per-worker load for Rendezvous).
+func StatusHandler(w http.ResponseWriter, r *http.Request) {
+	lb := Lb
+	if lb == nil {
+		http.Error(w, "no load balancer configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	var state interface{}
+	if reporter, ok := lb.(StatusReporter); ok {
+		state = reporter.Status()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(state)
+}
+
+// AdminStrategyHandler implements POST /admin/lb/strategy, letting an
+// operator switch the active strategy on a live cluster without a restart.
+// The body is {"strategy": "<name>", "config": {...}}, where config is
+// passed through to the named strategy's factory as-is.
+func AdminStrategyHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "must POST", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Strategy string          `json:"strategy"`
+		Config   json.RawMessage `json:"config"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if _, err := SetStrategy(req.Strategy, req.Config); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
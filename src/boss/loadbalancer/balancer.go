@@ -0,0 +1,47 @@
+package loadbalancer
+
+// Balancer decides which worker should serve an invocation, and is told how
+// that invocation turned out afterward so it can adapt its routing state.
+// KMeans, KModes and Random each implement it; InitLoadBalancer wires
+// whichever one Conf names into the global Lb singleton.
+type Balancer interface {
+	// SelectWorker picks one of req.Workers to serve req.
+	SelectWorker(req *InvocationRequest) (workerID string, err error)
+
+	// Observe reports the outcome of a request that was routed to
+	// workerID: the feature vector it was routed on, and how long it
+	// took to serve. Implementations that cluster on features (KMeans,
+	// KModes) use this to update their centroids/modes.
+	Observe(workerID string, features []float64, latencyMs float64)
+
+	// Features extracts the feature vector this strategy clusters/ranks
+	// on, in whatever shape its own SelectWorker/Observe logic expects
+	// (e.g. KMeans' NumericFeatures vs KModes' CategoricalFeatures).
+	// Callers must pass the same vector to Observe so a strategy never
+	// folds a foreign shape into its centroids/modes. Strategies that
+	// don't cluster on features (Random, Rendezvous) return nil.
+	Features(req *InvocationRequest) []float64
+}
+
+// StatusReporter is implemented by strategies that can describe their live
+// internal state (KMeans' centroids, KModes' modes, Rendezvous' per-worker
+// load) for GET /status/lb. For strategies that don't implement it,
+// GET /status/lb returns null.
+type StatusReporter interface {
+	Status() interface{}
+}
+
+// InvocationRequest is everything a Balancer needs to know about one
+// incoming invocation in order to pick a worker for it.
+type InvocationRequest struct {
+	Handler         string   // lambda/handler name
+	Packages        []string // imported packages
+	MemoryMB        int
+	RuntimeKind     string // e.g. "docker", "sock"
+	Language        string
+	BaseImageDigest string
+
+	// Workers lists the worker IDs currently eligible to serve this
+	// request.
+	Workers []string
+}
@@ -0,0 +1,149 @@
+package loadbalancer
+
+import (
+	"testing"
+)
+
+func TestKMeansSeedsFarthestFirst(t *testing.T) {
+	b := NewKMeansBalancer()
+
+	// A cluster of points near 0 and a cluster of points near 100, fed
+	// with every point near 0 first. FIFO seeding would pick the first
+	// NumGroup points and clump every centroid near 0; farthest-first
+	// seeding should instead spread the seeded centroids across both
+	// clusters.
+	points := [][]float64{
+		{0}, {1}, {2}, {3}, {4}, {0.5}, {1.5}, {2.5},
+		{100}, {101}, {102}, {103}, {104}, {100.5}, {101.5},
+	}
+	for _, p := range points {
+		b.Observe("w", p, 0)
+	}
+
+	if !b.seeded {
+		t.Fatalf("expected balancer to be seeded after %d observations (warmup=%d)", len(points), kmeansWarmup)
+	}
+
+	near0, near100 := 0, 0
+	for _, c := range b.centroids {
+		if c == nil {
+			t.Fatal("found an unseeded centroid after seeding")
+		}
+		if c[0] < 50 {
+			near0++
+		} else {
+			near100++
+		}
+	}
+	if near0 == 0 || near100 == 0 {
+		t.Fatalf("expected centroids spread across both clusters, got near0=%d near100=%d", near0, near100)
+	}
+}
+
+func TestKMeansOnlineUpdateAfterSeeding(t *testing.T) {
+	b := NewKMeansBalancer()
+	for i := 0; i < kmeansWarmup; i++ {
+		b.Observe("w", []float64{float64(i % NumGroup)}, 0)
+	}
+	if !b.seeded {
+		t.Fatal("expected balancer to be seeded")
+	}
+
+	cluster := b.nearestCluster([]float64{0})
+	before := append([]float64(nil), b.centroids[cluster]...)
+	b.Observe("w", []float64{0}, 0)
+	after := b.centroids[cluster]
+	if len(after) != len(before) {
+		t.Fatalf("centroid dimensionality changed: %v -> %v", before, after)
+	}
+}
+
+func TestKModesSeedsFarthestFirst(t *testing.T) {
+	b := NewKModesBalancer()
+
+	points := [][]float64{
+		{1, 1}, {9, 9}, {1, 1}, {9, 9}, {1, 1}, {9, 9},
+		{1, 1}, {9, 9}, {1, 1}, {9, 9}, {1, 1}, {9, 9},
+		{1, 1}, {9, 9}, {1, 1},
+	}
+	for _, p := range points {
+		b.Observe("w", p, 0)
+	}
+
+	if !b.seeded {
+		t.Fatalf("expected balancer to be seeded after %d observations (warmup=%d)", len(points), kmodesWarmup)
+	}
+
+	seenA, seenB := false, false
+	for _, m := range b.modes {
+		if m == nil {
+			t.Fatal("found an unseeded mode after seeding")
+		}
+		if m[0] == 1 {
+			seenA = true
+		} else {
+			seenB = true
+		}
+	}
+	if !seenA || !seenB {
+		t.Fatalf("expected modes spread across both distinct points, got modes=%v", b.modes)
+	}
+}
+
+func TestRendezvousStableForSameHandler(t *testing.T) {
+	b := NewRendezvousBalancer()
+	workers := []string{"w1", "w2", "w3"}
+	req := &InvocationRequest{Handler: "my-lambda", Workers: workers}
+
+	first, err := b.SelectWorker(req)
+	if err != nil {
+		t.Fatalf("SelectWorker: %v", err)
+	}
+	b.Observe(first, nil, 0)
+
+	for i := 0; i < 10; i++ {
+		got, err := b.SelectWorker(req)
+		if err != nil {
+			t.Fatalf("SelectWorker: %v", err)
+		}
+		b.Observe(got, nil, 0)
+		if got != first {
+			t.Fatalf("SelectWorker picked %q on iteration %d, want stable %q", got, i, first)
+		}
+	}
+}
+
+func TestRendezvousBoundedLoadFallsThrough(t *testing.T) {
+	b := NewBoundedLoadRendezvousBalancer(1.5)
+	workers := []string{"w1", "w2", "w3"}
+	req := &InvocationRequest{Handler: "my-lambda", Workers: workers}
+
+	top := rank(req.Handler, workers)[0]
+
+	// drive the top-ranked worker's load well past any reasonable cap so
+	// later selections must fall through to a different worker.
+	for i := 0; i < 20; i++ {
+		b.load[top]++
+	}
+
+	got, err := b.SelectWorker(req)
+	if err != nil {
+		t.Fatalf("SelectWorker: %v", err)
+	}
+	if got == top {
+		t.Fatalf("SelectWorker picked overloaded top-ranked worker %q instead of falling through", top)
+	}
+}
+
+func TestRendezvousObserveDecrementsLoad(t *testing.T) {
+	b := NewRendezvousBalancer()
+	b.load["w1"] = 1
+	b.Observe("w1", nil, 0)
+	if b.load["w1"] != 0 {
+		t.Fatalf("load[w1] = %d, want 0", b.load["w1"])
+	}
+	b.Observe("w1", nil, 0)
+	if b.load["w1"] != 0 {
+		t.Fatalf("load[w1] went negative: %d", b.load["w1"])
+	}
+}
@@ -1,23 +1,32 @@
 package loadbalancer
 
-const (
-	Random = 0
-	KMeans = 1
-	KModes = 2
-)
+import "encoding/json"
 
 const (
 	NumGroup = 5
 )
 
-var Lb *LoadBalancer
+// Lb is the active load balancing strategy. InitLoadBalancer assigns it at
+// startup; SetStrategy reassigns it at runtime (e.g. from
+// POST /admin/lb/strategy) so operators can A/B test strategies on a live
+// cluster without a restart.
+var Lb Balancer
 
-type LoadBalancer struct {
-	LbType int
+// InitLoadBalancer builds the named strategy (see Register) with the given
+// JSON config, taken from the open-lambda config file, and installs it as
+// Lb.
+func InitLoadBalancer(name string, cfg json.RawMessage) (Balancer, error) {
+	return SetStrategy(name, cfg)
 }
 
-func InitLoadBalancer() *LoadBalancer {
-	return &LoadBalancer{
-		LbType: Random,
+// SetStrategy builds the named strategy with cfg and installs it as Lb.
+// Requests already dispatched against the previous strategy are unaffected;
+// only new SelectWorker calls see the switch.
+func SetStrategy(name string, cfg json.RawMessage) (Balancer, error) {
+	lb, err := New(name, cfg)
+	if err != nil {
+		return nil, err
 	}
+	Lb = lb
+	return lb, nil
 }
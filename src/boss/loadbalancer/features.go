@@ -0,0 +1,48 @@
+package loadbalancer
+
+import "hash/fnv"
+
+// stringHash turns s into a stable float, so string-valued fields can share
+// the []float64 feature vectors Balancer.Observe is built around.
+func stringHash(s string) float64 {
+	h := fnv.New32a()
+	h.Write([]byte(s))
+	return float64(h.Sum32())
+}
+
+// bagHash folds a set of strings into a single stable, order-independent
+// float, for fields like imported packages where only membership matters.
+func bagHash(items []string) float64 {
+	var sum uint32
+	for _, item := range items {
+		h := fnv.New32a()
+		h.Write([]byte(item))
+		sum += h.Sum32()
+	}
+	return float64(sum)
+}
+
+// NumericFeatures extracts the feature vector KMeans clusters invocations
+// by: handler name hash, imported-package bag hash, requested memory (MB),
+// and recent average invocation latency in ms.
+func NumericFeatures(req *InvocationRequest, recentAvgLatencyMs float64) []float64 {
+	return []float64{
+		stringHash(req.Handler),
+		bagHash(req.Packages),
+		float64(req.MemoryMB),
+		recentAvgLatencyMs,
+	}
+}
+
+// CategoricalFeatures extracts the feature vector KModes clusters
+// invocations by: runtime kind, language, and base image digest. Each is
+// hashed to a float so it fits the same []float64 shape NumericFeatures
+// does, but KModes compares these dimensions for exact equality (Hamming
+// distance) rather than as continuous magnitudes.
+func CategoricalFeatures(req *InvocationRequest) []float64 {
+	return []float64{
+		stringHash(req.RuntimeKind),
+		stringHash(req.Language),
+		stringHash(req.BaseImageDigest),
+	}
+}
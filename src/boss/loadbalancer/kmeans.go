@@ -0,0 +1,214 @@
+package loadbalancer
+
+import (
+	"fmt"
+	"math"
+	"sync"
+)
+
+// kmeansWarmup is how many observations KMeansBalancer buffers before it
+// seeds its NumGroup centroids. It needs to be larger than NumGroup so
+// seeding actually has more candidates to choose among than slots to fill.
+const kmeansWarmup = NumGroup * 3
+
+// KMeansBalancer routes invocations by assigning them to one of NumGroup
+// centroids over NumericFeatures (handler hash, import-bag hash, memory MB,
+// recent avg latency) and routing to the worker cluster bound to the
+// nearest one. The first kmeansWarmup observations are buffered rather than
+// clustered; once the buffer fills, centroids are seeded k-means++-style
+// (the first centroid is the first buffered point, each one after that is
+// whichever buffered point is furthest from every already-seeded centroid)
+// and every buffered point is folded into its nearest centroid. From then
+// on, centroids are updated online with the standard streaming-mean
+// recurrence c_k <- c_k + (x - c_k) / n_k.
+type KMeansBalancer struct {
+	mu sync.Mutex
+
+	centroids [][]float64 // NumGroup entries; nil until seeded
+	counts    []int       // observations folded into each centroid so far
+	seeded    bool
+	pending   [][]float64 // buffered observations awaiting seeding
+
+	clusterWorkers [][]string // workers currently bound to each cluster
+	nextWorker     []int      // round-robin cursor per cluster
+
+	avgLatencyMs float64
+	latencyCount int
+}
+
+func NewKMeansBalancer() *KMeansBalancer {
+	return &KMeansBalancer{
+		centroids:      make([][]float64, NumGroup),
+		counts:         make([]int, NumGroup),
+		clusterWorkers: make([][]string, NumGroup),
+		nextWorker:     make([]int, NumGroup),
+	}
+}
+
+func euclidean(a, b []float64) float64 {
+	var sum float64
+	for i := range a {
+		d := a[i] - b[i]
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
+
+// nearestCluster returns the index of the seeded centroid closest to x, or
+// 0 if nothing has been seeded yet. Caller must hold b.mu.
+func (b *KMeansBalancer) nearestCluster(x []float64) int {
+	best, bestDist := -1, math.Inf(1)
+	for i, c := range b.centroids {
+		if c == nil {
+			continue
+		}
+		if d := euclidean(x, c); d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	if best == -1 {
+		return 0
+	}
+	return best
+}
+
+// seedFromPending picks NumGroup centroids out of b.pending via k-means++'s
+// farthest-first rule: the first centroid is the first buffered point, and
+// each subsequent one is whichever remaining buffered point maximizes its
+// distance to the nearest centroid chosen so far. Every buffered point not
+// picked as a centroid is then folded into its nearest cluster via the same
+// streaming-mean update Observe uses once seeded. Caller must hold b.mu.
+func (b *KMeansBalancer) seedFromPending() {
+	chosen := make([]bool, len(b.pending))
+
+	b.centroids[0] = b.pending[0]
+	b.counts[0] = 1
+	chosen[0] = true
+
+	for slot := 1; slot < NumGroup; slot++ {
+		best, bestDist := -1, -1.0
+		for i, p := range b.pending {
+			if chosen[i] {
+				continue
+			}
+			nearest := math.Inf(1)
+			for c := 0; c < slot; c++ {
+				if d := euclidean(p, b.centroids[c]); d < nearest {
+					nearest = d
+				}
+			}
+			if nearest > bestDist {
+				best, bestDist = i, nearest
+			}
+		}
+		b.centroids[slot] = b.pending[best]
+		b.counts[slot] = 1
+		chosen[best] = true
+	}
+
+	for i, p := range b.pending {
+		if chosen[i] {
+			continue
+		}
+		cluster := b.nearestCluster(p)
+		b.counts[cluster]++
+		n := float64(b.counts[cluster])
+		centroid := b.centroids[cluster]
+		for j, v := range p {
+			centroid[j] += (v - centroid[j]) / n
+		}
+	}
+
+	b.pending = nil
+	b.seeded = true
+}
+
+func (b *KMeansBalancer) SelectWorker(req *InvocationRequest) (string, error) {
+	if len(req.Workers) == 0 {
+		return "", fmt.Errorf("loadbalancer: no workers available")
+	}
+
+	b.mu.Lock()
+	x := NumericFeatures(req, b.avgLatencyMs)
+	cluster := b.nearestCluster(x)
+	workers := b.clusterWorkers[cluster]
+	if len(workers) == 0 {
+		workers = req.Workers
+	}
+	idx := b.nextWorker[cluster] % len(workers)
+	b.nextWorker[cluster]++
+	b.mu.Unlock()
+
+	return workers[idx], nil
+}
+
+// Features returns NumericFeatures(req, b.avgLatencyMs): the same vector
+// SelectWorker clusters on, so callers that thread Features' result through
+// to Observe keep the centroids in one consistent shape.
+func (b *KMeansBalancer) Features(req *InvocationRequest) []float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return NumericFeatures(req, b.avgLatencyMs)
+}
+
+func (b *KMeansBalancer) Observe(workerID string, features []float64, latencyMs float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.latencyCount++
+	b.avgLatencyMs += (latencyMs - b.avgLatencyMs) / float64(b.latencyCount)
+
+	if !b.seeded {
+		b.pending = append(b.pending, append([]float64(nil), features...))
+		if len(b.pending) >= kmeansWarmup {
+			b.seedFromPending()
+		}
+		return
+	}
+
+	cluster := b.nearestCluster(features)
+	b.counts[cluster]++
+	n := float64(b.counts[cluster])
+	centroid := b.centroids[cluster]
+	for i, v := range features {
+		centroid[i] += (v - centroid[i]) / n
+	}
+}
+
+// Status reports each cluster's centroid, observation count, and bound
+// workers, for GET /status/lb.
+func (b *KMeansBalancer) Status() interface{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	type cluster struct {
+		Centroid []float64 `json:"centroid"`
+		Count    int       `json:"count"`
+		Workers  []string  `json:"workers"`
+	}
+	clusters := make([]cluster, NumGroup)
+	for i := range clusters {
+		clusters[i] = cluster{
+			Centroid: b.centroids[i],
+			Count:    b.counts[i],
+			Workers:  b.clusterWorkers[i],
+		}
+	}
+	return struct {
+		AvgLatencyMs float64   `json:"avg_latency_ms"`
+		Clusters     []cluster `json:"clusters"`
+	}{
+		AvgLatencyMs: b.avgLatencyMs,
+		Clusters:     clusters,
+	}
+}
+
+// Rebind updates which workers are bound to each cluster, e.g. after the
+// worker pool scales up or down.
+func (b *KMeansBalancer) Rebind(clusterWorkers [][]string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i := 0; i < NumGroup && i < len(clusterWorkers); i++ {
+		b.clusterWorkers[i] = clusterWorkers[i]
+	}
+}
@@ -0,0 +1,207 @@
+package loadbalancer
+
+import (
+	"fmt"
+	"sync"
+)
+
+// kmodesWarmup mirrors kmeansWarmup: KModesBalancer buffers this many
+// observations before seeding, so seeding has more candidates than slots.
+const kmodesWarmup = NumGroup * 3
+
+// KModesBalancer is KMeansBalancer's counterpart for categorical features
+// (runtime kind, language, base image digest). It clusters on Hamming
+// distance instead of Euclidean distance, and instead of averaging each
+// dimension it tracks a per-dimension frequency count and takes the most
+// common value seen so far as that dimension's "mode". Like KMeansBalancer,
+// it buffers the first kmodesWarmup observations and seeds its NumGroup
+// modes k-modes++-style (the first mode is the first buffered point, each
+// one after that is whichever buffered point disagrees most, by Hamming
+// distance, with the nearest already-seeded mode) before folding every
+// buffered point into its nearest cluster.
+type KModesBalancer struct {
+	mu sync.Mutex
+
+	modes   [][]float64               // NumGroup entries; nil until seeded
+	freq    []map[int]map[float64]int // per cluster, per dimension: value -> count
+	seeded  bool
+	pending [][]float64 // buffered observations awaiting seeding
+
+	clusterWorkers [][]string
+	nextWorker     []int
+}
+
+func NewKModesBalancer() *KModesBalancer {
+	return &KModesBalancer{
+		modes:          make([][]float64, NumGroup),
+		freq:           make([]map[int]map[float64]int, NumGroup),
+		clusterWorkers: make([][]string, NumGroup),
+		nextWorker:     make([]int, NumGroup),
+	}
+}
+
+// hamming counts the dimensions where a and b disagree.
+func hamming(a, b []float64) int {
+	d := 0
+	for i := range a {
+		if a[i] != b[i] {
+			d++
+		}
+	}
+	return d
+}
+
+// nearestCluster returns the index of the seeded mode closest to x by
+// Hamming distance, or 0 if nothing has been seeded yet. Caller must hold
+// b.mu.
+func (b *KModesBalancer) nearestCluster(x []float64) int {
+	best, bestDist := -1, -1
+	for i, m := range b.modes {
+		if m == nil {
+			continue
+		}
+		if d := hamming(x, m); best == -1 || d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	if best == -1 {
+		return 0
+	}
+	return best
+}
+
+// foldInto merges x into cluster's per-dimension frequency tables and
+// recomputes that cluster's mode as the per-dimension majority value.
+// Caller must hold b.mu.
+func (b *KModesBalancer) foldInto(cluster int, x []float64) {
+	if b.freq[cluster] == nil {
+		b.freq[cluster] = make(map[int]map[float64]int, len(x))
+	}
+	mode := b.modes[cluster]
+	for i, v := range x {
+		if b.freq[cluster][i] == nil {
+			b.freq[cluster][i] = map[float64]int{}
+		}
+		counts := b.freq[cluster][i]
+		counts[v]++
+
+		best, bestCount := mode[i], 0
+		for val, n := range counts {
+			if n > bestCount {
+				best, bestCount = val, n
+			}
+		}
+		mode[i] = best
+	}
+}
+
+// seedFromPending picks NumGroup modes out of b.pending via k-modes++'s
+// farthest-first rule (by Hamming distance), then folds every buffered
+// point -- including the ones picked as modes -- into its nearest cluster's
+// frequency tables. Caller must hold b.mu.
+func (b *KModesBalancer) seedFromPending() {
+	chosen := make([]bool, len(b.pending))
+
+	b.modes[0] = b.pending[0]
+	chosen[0] = true
+
+	for slot := 1; slot < NumGroup; slot++ {
+		best, bestDist := -1, -1
+		for i, p := range b.pending {
+			if chosen[i] {
+				continue
+			}
+			nearest := -1
+			for c := 0; c < slot; c++ {
+				if d := hamming(p, b.modes[c]); nearest == -1 || d < nearest {
+					nearest = d
+				}
+			}
+			if nearest > bestDist {
+				best, bestDist = i, nearest
+			}
+		}
+		b.modes[slot] = b.pending[best]
+		chosen[best] = true
+	}
+
+	for _, p := range b.pending {
+		b.foldInto(b.nearestCluster(p), p)
+	}
+
+	b.pending = nil
+	b.seeded = true
+}
+
+func (b *KModesBalancer) SelectWorker(req *InvocationRequest) (string, error) {
+	if len(req.Workers) == 0 {
+		return "", fmt.Errorf("loadbalancer: no workers available")
+	}
+
+	b.mu.Lock()
+	x := CategoricalFeatures(req)
+	cluster := b.nearestCluster(x)
+	workers := b.clusterWorkers[cluster]
+	if len(workers) == 0 {
+		workers = req.Workers
+	}
+	idx := b.nextWorker[cluster] % len(workers)
+	b.nextWorker[cluster]++
+	b.mu.Unlock()
+
+	return workers[idx], nil
+}
+
+// Features returns CategoricalFeatures(req): the same vector SelectWorker
+// clusters on, so callers that thread Features' result through to Observe
+// keep the modes in one consistent shape.
+func (b *KModesBalancer) Features(req *InvocationRequest) []float64 {
+	return CategoricalFeatures(req)
+}
+
+func (b *KModesBalancer) Observe(workerID string, features []float64, latencyMs float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.seeded {
+		b.pending = append(b.pending, append([]float64(nil), features...))
+		if len(b.pending) >= kmodesWarmup {
+			b.seedFromPending()
+		}
+		return
+	}
+
+	b.foldInto(b.nearestCluster(features), features)
+}
+
+// Status reports each cluster's mode vector and bound workers, for
+// GET /status/lb.
+func (b *KModesBalancer) Status() interface{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	type cluster struct {
+		Mode    []float64 `json:"mode"`
+		Workers []string  `json:"workers"`
+	}
+	clusters := make([]cluster, NumGroup)
+	for i := range clusters {
+		clusters[i] = cluster{
+			Mode:    b.modes[i],
+			Workers: b.clusterWorkers[i],
+		}
+	}
+	return struct {
+		Clusters []cluster `json:"clusters"`
+	}{Clusters: clusters}
+}
+
+// Rebind updates which workers are bound to each cluster, e.g. after the
+// worker pool scales up or down.
+func (b *KModesBalancer) Rebind(clusterWorkers [][]string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i := 0; i < NumGroup && i < len(clusterWorkers); i++ {
+		b.clusterWorkers[i] = clusterWorkers[i]
+	}
+}
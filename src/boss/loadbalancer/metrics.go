@@ -0,0 +1,61 @@
+package loadbalancer
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	metricDecisionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ol_lb_decisions_total",
+		Help: "Total load balancing decisions, by strategy and chosen worker.",
+	}, []string{"strategy", "worker"})
+
+	metricDecisionLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ol_lb_decision_latency_seconds",
+		Help:    "Time taken to pick a worker for an invocation.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"strategy"})
+
+	metricWorkerInflight = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ol_lb_worker_inflight",
+		Help: "Invocations currently routed to each worker and not yet Observe()'d.",
+	}, []string{"worker"})
+)
+
+// instrumented wraps a Balancer so every strategy gets the same
+// ol_lb_decisions_total/ol_lb_decision_latency_seconds/ol_lb_worker_inflight
+// metrics for free, without each strategy having to record them itself.
+type instrumented struct {
+	name string
+	Balancer
+}
+
+func (b *instrumented) SelectWorker(req *InvocationRequest) (string, error) {
+	start := time.Now()
+	workerID, err := b.Balancer.SelectWorker(req)
+	metricDecisionLatency.WithLabelValues(b.name).Observe(time.Since(start).Seconds())
+	if err != nil {
+		return "", err
+	}
+
+	metricDecisionsTotal.WithLabelValues(b.name, workerID).Inc()
+	metricWorkerInflight.WithLabelValues(workerID).Inc()
+	return workerID, nil
+}
+
+func (b *instrumented) Observe(workerID string, features []float64, latencyMs float64) {
+	metricWorkerInflight.WithLabelValues(workerID).Dec()
+	b.Balancer.Observe(workerID, features, latencyMs)
+}
+
+// Status returns the wrapped strategy's live internal state, if it
+// implements StatusReporter.
+func (b *instrumented) Status() interface{} {
+	if reporter, ok := b.Balancer.(StatusReporter); ok {
+		return reporter.Status()
+	}
+	return nil
+}
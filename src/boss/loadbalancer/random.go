@@ -0,0 +1,27 @@
+package loadbalancer
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// RandomBalancer is the trivial strategy: pick any eligible worker
+// uniformly at random. It predates KMeans/KModes and remains the default
+// when no smarter strategy is configured.
+type RandomBalancer struct{}
+
+func NewRandomBalancer() *RandomBalancer {
+	return &RandomBalancer{}
+}
+
+func (b *RandomBalancer) SelectWorker(req *InvocationRequest) (string, error) {
+	if len(req.Workers) == 0 {
+		return "", fmt.Errorf("loadbalancer: no workers available")
+	}
+	return req.Workers[rand.Intn(len(req.Workers))], nil
+}
+
+func (b *RandomBalancer) Observe(workerID string, features []float64, latencyMs float64) {}
+
+// Features is unused: RandomBalancer doesn't cluster on anything.
+func (b *RandomBalancer) Features(req *InvocationRequest) []float64 { return nil }
@@ -0,0 +1,76 @@
+package loadbalancer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// Factory builds a Balancer from its JSON config (the strategy's section of
+// the open-lambda config file, or a POST /admin/lb/strategy body). cfg may
+// be nil/empty; factories should apply sensible defaults in that case.
+type Factory func(cfg json.RawMessage) (Balancer, error)
+
+var (
+	registryMu sync.Mutex
+	registry   = map[string]Factory{}
+)
+
+// Register adds a named strategy to the registry so it can be selected by
+// name from the config file or a runtime strategy switch. Built-in
+// strategies register themselves from init(); callers can Register their
+// own the same way.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("loadbalancer: strategy %q already registered", name))
+	}
+	registry[name] = factory
+}
+
+// New builds the named strategy with the given config, wrapped so its
+// decisions are instrumented (see metrics.go).
+func New(name string, cfg json.RawMessage) (Balancer, error) {
+	registryMu.Lock()
+	factory, ok := registry[name]
+	registryMu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("loadbalancer: unknown strategy %q", name)
+	}
+
+	lb, err := factory(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("loadbalancer: building strategy %q: %w", name, err)
+	}
+	return &instrumented{name: name, Balancer: lb}, nil
+}
+
+func init() {
+	Register("random", func(cfg json.RawMessage) (Balancer, error) {
+		return NewRandomBalancer(), nil
+	})
+
+	Register("kmeans", func(cfg json.RawMessage) (Balancer, error) {
+		return NewKMeansBalancer(), nil
+	})
+
+	Register("kmodes", func(cfg json.RawMessage) (Balancer, error) {
+		return NewKModesBalancer(), nil
+	})
+
+	Register("rendezvous", func(cfg json.RawMessage) (Balancer, error) {
+		var opts struct {
+			CapFactor float64 `json:"cap_factor"`
+		}
+		if len(cfg) > 0 {
+			if err := json.Unmarshal(cfg, &opts); err != nil {
+				return nil, fmt.Errorf("invalid rendezvous config: %w", err)
+			}
+		}
+		if opts.CapFactor > 0 {
+			return NewBoundedLoadRendezvousBalancer(opts.CapFactor), nil
+		}
+		return NewRendezvousBalancer(), nil
+	})
+}
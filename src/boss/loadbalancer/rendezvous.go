@@ -0,0 +1,135 @@
+package loadbalancer
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+)
+
+// RendezvousBalancer implements Highest Random Weight (rendezvous) hashing:
+// for each (handler, worker) pair it computes h = hash(handler||worker) and
+// routes to the worker with the highest h. Every caller computes the same
+// ranking independently, so invocations of the same handler keep landing on
+// the same worker (reusing its warm sandbox / import cache) without any
+// shared ring structure, and adding or removing a worker only reshuffles
+// the invocations that would have hashed to it.
+//
+// If capFactor > 0, it also bounds load: a worker is skipped in favor of
+// the next-highest-weight one once its in-flight count reaches
+// capFactor * average in-flight count across req.Workers (the cap is never
+// below 1). If every worker is at or over the cap, the highest-weight one is
+// chosen anyway.
+type RendezvousBalancer struct {
+	mu        sync.Mutex
+	load      map[string]int
+	capFactor float64
+}
+
+// NewRendezvousBalancer returns a plain HRW balancer with no load bound.
+func NewRendezvousBalancer() *RendezvousBalancer {
+	return &RendezvousBalancer{load: map[string]int{}}
+}
+
+// NewBoundedLoadRendezvousBalancer returns an HRW balancer that caps each
+// worker at capFactor times the average in-flight count, falling through to
+// the next-highest-weight worker when the top choice is over cap.
+func NewBoundedLoadRendezvousBalancer(capFactor float64) *RendezvousBalancer {
+	return &RendezvousBalancer{load: map[string]int{}, capFactor: capFactor}
+}
+
+// weight is the HRW score for (handler, worker): a stable hash of their
+// concatenation, so every caller ranks workers identically without
+// coordination.
+func weight(handler, worker string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(handler))
+	h.Write([]byte("||"))
+	h.Write([]byte(worker))
+	return h.Sum64()
+}
+
+// rank returns req.Workers sorted by descending HRW weight for req.Handler.
+func rank(handler string, workers []string) []string {
+	ranked := append([]string(nil), workers...)
+	sort.Slice(ranked, func(i, j int) bool {
+		return weight(handler, ranked[i]) > weight(handler, ranked[j])
+	})
+	return ranked
+}
+
+func (b *RendezvousBalancer) avgLoad(workers []string) float64 {
+	if len(workers) == 0 {
+		return 0
+	}
+	total := 0
+	for _, w := range workers {
+		total += b.load[w]
+	}
+	return float64(total) / float64(len(workers))
+}
+
+func (b *RendezvousBalancer) SelectWorker(req *InvocationRequest) (string, error) {
+	if len(req.Workers) == 0 {
+		return "", fmt.Errorf("loadbalancer: no workers available")
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ranked := rank(req.Handler, req.Workers)
+	if b.capFactor <= 0 {
+		chosen := ranked[0]
+		b.load[chosen]++
+		return chosen, nil
+	}
+
+	capLimit := b.capFactor * b.avgLoad(req.Workers)
+	if capLimit < 1 {
+		capLimit = 1
+	}
+	for _, w := range ranked {
+		if float64(b.load[w]) < capLimit {
+			b.load[w]++
+			return w, nil
+		}
+	}
+
+	// every worker is over cap; take the highest-weight one anyway
+	// rather than reject the request.
+	chosen := ranked[0]
+	b.load[chosen]++
+	return chosen, nil
+}
+
+// Features is unused: RendezvousBalancer ranks workers by HRW hash of
+// (handler, worker), not by clustering on a feature vector.
+func (b *RendezvousBalancer) Features(req *InvocationRequest) []float64 { return nil }
+
+func (b *RendezvousBalancer) Observe(workerID string, features []float64, latencyMs float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.load[workerID] > 0 {
+		b.load[workerID]--
+	}
+}
+
+// Status reports the cap factor and each worker's current in-flight count,
+// for GET /status/lb. There's no ring to dump: HRW ranks workers on the fly
+// from (handler, worker) hashes rather than maintaining one.
+func (b *RendezvousBalancer) Status() interface{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	load := make(map[string]int, len(b.load))
+	for w, n := range b.load {
+		load[w] = n
+	}
+	return struct {
+		CapFactor float64        `json:"cap_factor,omitempty"`
+		Load      map[string]int `json:"load"`
+	}{
+		CapFactor: b.capFactor,
+		Load:      load,
+	}
+}
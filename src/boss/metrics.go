@@ -0,0 +1,119 @@
+package boss
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	metricWorkers = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ol_workers",
+		Help: "Number of workers currently in each pool state.",
+	}, []string{"state"})
+
+	metricWorkersTarget = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ol_workers_target",
+		Help: "Configured target size of the worker pool.",
+	})
+
+	metricQueueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ol_queue_depth",
+		Help: "Number of workers currently sitting in the ready queue.",
+	})
+
+	metricInvocationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ol_invocations_total",
+		Help: "Total lambda invocations handled by the boss.",
+	}, []string{"lambda", "status"})
+
+	metricWorkerLaunchesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ol_worker_launches_total",
+		Help: "Total worker launch attempts, by result.",
+	}, []string{"result"})
+
+	metricWorkerDestroysTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "ol_worker_destroys_total",
+		Help: "Total worker instances destroyed.",
+	})
+
+	metricInvocationLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ol_invocation_latency_seconds",
+		Help:    "Latency of lambda invocations forwarded to a worker.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"lambda"})
+)
+
+// MetricsHandler exposes the boss's Prometheus metrics for scraping at
+// /metrics.
+func MetricsHandler() http.Handler {
+	return promhttp.Handler()
+}
+
+// reportClusterSizes refreshes the ol_workers*/ol_queue_depth gauges. It's
+// called from updateCluster any time the shape of the cluster might have
+// changed.
+func (pool *WorkerPool) reportClusterSizes() {
+	pool.Lock()
+	starting := len(pool.workers[STARTING])
+	running := len(pool.workers[RUNNING])
+	cleaning := len(pool.workers[CLEANING])
+	destroying := len(pool.workers[DESTROYING])
+	target := pool.target
+	pool.Unlock()
+
+	metricWorkers.WithLabelValues("starting").Set(float64(starting))
+	metricWorkers.WithLabelValues("running").Set(float64(running))
+	metricWorkers.WithLabelValues("cleaning").Set(float64(cleaning))
+	metricWorkers.WithLabelValues("destroying").Set(float64(destroying))
+	metricWorkersTarget.Set(float64(target))
+	metricQueueDepth.Set(float64(len(pool.queue)))
+}
+
+// InstanceView is a snapshot of one worker, as returned by /instances.json.
+type InstanceView struct {
+	ID       string `json:"id"`
+	Address  string `json:"address"`
+	State    string `json:"state"`
+	NumTask  int32  `json:"numTask"`
+	// LastBusy is the RFC3339 timestamp this worker last finished serving
+	// an invocation, omitted if it never has.
+	LastBusy string `json:"last_busy,omitempty"`
+}
+
+var stateNames = map[WorkerState]string{
+	STARTING:   "starting",
+	RUNNING:    "running",
+	CLEANING:   "cleaning",
+	DESTROYING: "destroying",
+}
+
+// Instances implements GET /instances.json, returning a snapshot of every
+// worker the boss currently knows about.
+func (pool *WorkerPool) Instances(w http.ResponseWriter, r *http.Request) {
+	pool.Lock()
+	views := make([]InstanceView, 0)
+	for state, bucket := range pool.workers {
+		for _, worker := range bucket {
+			view := InstanceView{
+				ID:      worker.workerId,
+				Address: worker.workerIp,
+				State:   stateNames[WorkerState(state)],
+				NumTask: worker.numTask,
+			}
+			if nanos := atomic.LoadInt64(&worker.lastBusyUnixNano); nanos != 0 {
+				view.LastBusy = time.Unix(0, nanos).UTC().Format(time.RFC3339)
+			}
+			views = append(views, view)
+		}
+	}
+	pool.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(views)
+}
@@ -0,0 +1,33 @@
+package boss
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/open-lambda/open-lambda/ol/boss/loadbalancer"
+)
+
+// RegisterRoutes wires every HTTP-facing piece of the boss onto mux: lambda
+// invocation, cluster/task status, idle-behavior control, Prometheus
+// metrics, and the load balancer's admin endpoints. Build pool with
+// NewWorkerPool and call this once before starting the server.
+func RegisterRoutes(mux *http.ServeMux, pool *WorkerPool) {
+	mux.HandleFunc("/run/", pool.RunLambda)
+	mux.HandleFunc("/status/tasks", pool.statusTasksHandler)
+	mux.HandleFunc("/status/cluster", pool.statusClusterHandler)
+	mux.HandleFunc("/instances.json", pool.Instances)
+	mux.HandleFunc("/workers/", pool.SetIdleBehavior)
+	mux.Handle("/metrics", MetricsHandler())
+	mux.HandleFunc("/status/lb", loadbalancer.StatusHandler)
+	mux.HandleFunc("/admin/lb/strategy", loadbalancer.AdminStrategyHandler)
+}
+
+func (pool *WorkerPool) statusTasksHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pool.StatusTasks())
+}
+
+func (pool *WorkerPool) statusClusterHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pool.StatusCluster())
+}
@@ -0,0 +1,276 @@
+package boss
+
+import (
+	"container/heap"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/open-lambda/open-lambda/ol/boss/loadbalancer"
+)
+
+// InvocationRequest describes one HTTP invocation waiting to be dispatched
+// to a worker. Requests for the same lambda are served in priority order
+// (highest first), ties broken by earliest SubmitTime.
+type InvocationRequest struct {
+	LambdaName string
+	Priority   int
+	SubmitTime time.Time
+
+	// Packages, MemoryMB, RuntimeKind, Language, and BaseImageDigest are
+	// the per-invocation metadata loadbalancer.InvocationRequest needs to
+	// cluster on (see the X-OL-* headers parsed in RunLambda). They're
+	// zero-valued unless the caller sets the corresponding header.
+	Packages        []string
+	MemoryMB        int
+	RuntimeKind     string
+	Language        string
+	BaseImageDigest string
+
+	w    http.ResponseWriter
+	r    *http.Request
+	done chan struct{}
+}
+
+// lambdaNameFromRequest pulls the lambda name out of a /run/<lambda-name>
+// request path, the same convention server.getUrlComponents uses on the
+// worker side.
+func lambdaNameFromRequest(r *http.Request) string {
+	path := strings.Trim(r.URL.Path, "/")
+	parts := strings.Split(path, "/")
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[1]
+}
+
+// priorityQueue is a container/heap.Interface over pending invocations.
+type priorityQueue []*InvocationRequest
+
+func (q priorityQueue) Len() int { return len(q) }
+
+func (q priorityQueue) Less(i, j int) bool {
+	if q[i].Priority != q[j].Priority {
+		return q[i].Priority > q[j].Priority
+	}
+	return q[i].SubmitTime.Before(q[j].SubmitTime)
+}
+
+func (q priorityQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *priorityQueue) Push(x interface{}) { *q = append(*q, x.(*InvocationRequest)) }
+
+func (q *priorityQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	*q = old[:n-1]
+	return item
+}
+
+// Scheduler sits between the HTTP handler and forwardTask. It replaces the
+// old round-robin `pool.queue <- worker; <-pool.queue` dispatch with an
+// explicit priority queue plus per-lambda concurrency limits, so a burst of
+// invocations for one lambda can't starve every other lambda of workers.
+type Scheduler struct {
+	pool *WorkerPool
+
+	mu       sync.Mutex
+	q        priorityQueue
+	inFlight map[string]int
+	lastUsed map[string]string // lambda name -> workerId, for warm-cache affinity
+}
+
+func NewScheduler(pool *WorkerPool) *Scheduler {
+	s := &Scheduler{
+		pool:     pool,
+		inFlight: map[string]int{},
+		lastUsed: map[string]string{},
+	}
+	go s.dispatchLoop()
+	return s
+}
+
+func (s *Scheduler) maxConcurrency(lambdaName string) int {
+	if limit, ok := Conf.Lambda_Limits[lambdaName]; ok && limit > 0 {
+		return limit
+	}
+	return Conf.Default_Max_Concurrency
+}
+
+// Submit enqueues req and blocks until it has been dispatched to a worker
+// and served. It returns false (having already written a response) if
+// admission control rejected the request outright because the queue was
+// too deep.
+func (s *Scheduler) Submit(req *InvocationRequest) bool {
+	s.mu.Lock()
+	if len(s.q) >= Conf.Queue_Max_Depth {
+		s.mu.Unlock()
+		req.w.Header().Set("Retry-After", "1")
+		req.w.WriteHeader(http.StatusTooManyRequests)
+		req.w.Write([]byte("queue is full, try again later\n"))
+		return false
+	}
+	req.done = make(chan struct{})
+	heap.Push(&s.q, req)
+	s.mu.Unlock()
+
+	<-req.done
+	return true
+}
+
+// dispatchLoop is the only goroutine that removes requests from the queue
+// and hands them to a worker.
+func (s *Scheduler) dispatchLoop() {
+	for {
+		s.mu.Lock()
+		req := s.nextAdmissible()
+		if req == nil {
+			s.mu.Unlock()
+			time.Sleep(5 * time.Millisecond)
+			continue
+		}
+		s.inFlight[req.LambdaName]++
+		s.mu.Unlock()
+
+		go s.dispatch(req)
+	}
+}
+
+// nextAdmissible picks the highest-priority (earliest-submitted on ties)
+// queued request whose lambda is still under its concurrency limit, and
+// removes it from the queue. Caller must hold s.mu.
+func (s *Scheduler) nextAdmissible() *InvocationRequest {
+	best := -1
+	for i, req := range s.q {
+		if s.inFlight[req.LambdaName] >= s.maxConcurrency(req.LambdaName) {
+			continue
+		}
+		if best == -1 || s.q.Less(i, best) {
+			best = i
+		}
+	}
+	if best == -1 {
+		return nil
+	}
+	return heap.Remove(&s.q, best).(*InvocationRequest)
+}
+
+// dispatch picks a worker for req and forwards the request to it.
+func (s *Scheduler) dispatch(req *InvocationRequest) {
+	defer func() {
+		s.mu.Lock()
+		s.inFlight[req.LambdaName]--
+		s.mu.Unlock()
+		close(req.done)
+	}()
+
+	worker := s.pickWorker(req)
+	if worker == nil {
+		// pool.ctx was canceled (Close() is shutting us down) while we
+		// had nothing ready to serve req.
+		req.w.WriteHeader(http.StatusServiceUnavailable)
+		req.w.Write([]byte("worker pool shutting down\n"))
+		return
+	}
+
+	atomic.AddInt32(&worker.numTask, 1)
+	defer atomic.AddInt32(&worker.numTask, -1)
+	defer atomic.StoreInt64(&worker.lastBusyUnixNano, time.Now().UnixNano())
+
+	s.mu.Lock()
+	s.lastUsed[req.LambdaName] = worker.workerId
+	s.mu.Unlock()
+
+	start := time.Now()
+	forwardTask(req.w, req.r, worker.workerIp)
+
+	if loadbalancer.Lb != nil {
+		features := loadbalancer.Lb.Features(lbInvocationRequest(req, nil))
+		loadbalancer.Lb.Observe(worker.workerId, features, float64(time.Since(start).Milliseconds()))
+	}
+}
+
+// lbInvocationRequest builds the loadbalancer.InvocationRequest for req,
+// carrying over the per-invocation metadata parsed from its X-OL-* headers
+// in RunLambda so every strategy's Features/SelectWorker sees the real
+// handler, packages, memory, runtime, language, and base image -- not just
+// the handler name.
+func lbInvocationRequest(req *InvocationRequest, workers []string) *loadbalancer.InvocationRequest {
+	return &loadbalancer.InvocationRequest{
+		Handler:         req.LambdaName,
+		Packages:        req.Packages,
+		MemoryMB:        req.MemoryMB,
+		RuntimeKind:     req.RuntimeKind,
+		Language:        req.Language,
+		BaseImageDigest: req.BaseImageDigest,
+		Workers:         workers,
+	}
+}
+
+// pickWorker borrows every worker currently sitting in pool.queue and asks
+// loadbalancer.Lb which one should serve req. If Lb has nothing to say (no
+// strategy configured, or it picked a candidate that raced out of the queue
+// in the meantime) it falls back to preferring whichever worker last served
+// req.LambdaName (so its warm sandbox / import cache gets reused), then the
+// least-loaded one. It returns every candidate, the chosen one included, to
+// the queue before returning. It returns nil if pool.ctx is canceled while waiting for a
+// worker, so Close() can't hang forever on a request that will never be
+// served.
+func (s *Scheduler) pickWorker(req *InvocationRequest) *Worker {
+	s.mu.Lock()
+	preferredId := s.lastUsed[req.LambdaName]
+	s.mu.Unlock()
+
+	n := len(s.pool.queue)
+	candidates := make([]*Worker, 0, n+1)
+	if n == 0 {
+		// nothing ready yet; block for the next worker to arrive
+		select {
+		case w := <-s.pool.queue:
+			candidates = append(candidates, w)
+		case <-s.pool.ctx.Done():
+			return nil
+		}
+	} else {
+		for i := 0; i < n; i++ {
+			candidates = append(candidates, <-s.pool.queue)
+		}
+	}
+
+	var chosen *Worker
+	if loadbalancer.Lb != nil {
+		ids := make([]string, len(candidates))
+		for i, w := range candidates {
+			ids[i] = w.workerId
+		}
+		if id, err := loadbalancer.Lb.SelectWorker(lbInvocationRequest(req, ids)); err == nil {
+			for _, w := range candidates {
+				if w.workerId == id {
+					chosen = w
+					break
+				}
+			}
+		}
+	}
+
+	if chosen == nil {
+		for _, w := range candidates {
+			if w.workerId == preferredId {
+				chosen = w
+				break
+			}
+			if chosen == nil || atomic.LoadInt32(&w.numTask) < atomic.LoadInt32(&chosen.numTask) {
+				chosen = w
+			}
+		}
+	}
+
+	for _, w := range candidates {
+		s.pool.queue <- w
+	}
+
+	return chosen
+}
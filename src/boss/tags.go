@@ -0,0 +1,22 @@
+package boss
+
+// Well-known InstanceTags keys the boss stores on every instance it creates,
+// so that state which would otherwise only live in the WorkerPool's maps can
+// be recovered from the cloud provider itself (e.g. after a boss restart).
+const (
+	// tagKeyInstanceSetID marks every instance created by this boss's
+	// InstanceSet, as opposed to instances some other InstanceSetID (or
+	// another application entirely) happens to have created in the same
+	// account/project.
+	tagKeyInstanceSetID = "ol-instance-set-id"
+
+	// tagKeyWorkerID records the WorkerPool-assigned worker ID, so a
+	// recovered instance can be re-inserted into pool.workers under the
+	// same key it would have had if the boss had never restarted.
+	tagKeyWorkerID = "ol-worker-id"
+)
+
+// instanceSetID identifies the set of instances this boss process owns.
+// It is constant for now; a future change could make it configurable so
+// multiple bosses can safely share a cloud account/project.
+const instanceSetID = "default"
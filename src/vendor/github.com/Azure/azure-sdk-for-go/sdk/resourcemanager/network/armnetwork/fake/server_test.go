@@ -0,0 +1,49 @@
+//go:build go1.18
+// +build go1.18
+
+package fake
+
+import "testing"
+
+// These exercise the fake transport's own routing logic (regex patterns and
+// path-param extraction) directly, without going through azfake.Responder/
+// PollerResponder construction -- this vendor tree doesn't carry the rest of
+// the sdk/azcore/fake test helpers needed to build those, so a true
+// in-process round trip through VirtualRoutersServerTransport.Do isn't
+// buildable here.
+
+func TestVirtualRouterURLPatternAndPathParams(t *testing.T) {
+	path := "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualRouters/vr1"
+	if !virtualRouterURLPattern.MatchString(path) {
+		t.Fatalf("virtualRouterURLPattern did not match %q", path)
+	}
+	params := pathParams(virtualRouterURLPattern, path)
+	if params["rg"] != "rg1" || params["name"] != "vr1" {
+		t.Fatalf("pathParams(%q) = %v, want rg=rg1 name=vr1", path, params)
+	}
+
+	if virtualRouterURLPattern.MatchString("/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualRouters") {
+		t.Fatal("virtualRouterURLPattern should not match the list-by-resource-group path")
+	}
+}
+
+func TestVirtualRouterListByResourceGroupURLPattern(t *testing.T) {
+	path := "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualRouters"
+	if !virtualRouterListByResourceGroupURLPattern.MatchString(path) {
+		t.Fatalf("virtualRouterListByResourceGroupURLPattern did not match %q", path)
+	}
+	params := pathParams(virtualRouterListByResourceGroupURLPattern, path)
+	if params["rg"] != "rg1" {
+		t.Fatalf("pathParams(%q) = %v, want rg=rg1", path, params)
+	}
+}
+
+func TestVirtualRouterListURLPattern(t *testing.T) {
+	path := "/subscriptions/sub1/providers/Microsoft.Network/virtualRouters"
+	if !virtualRouterListURLPattern.MatchString(path) {
+		t.Fatalf("virtualRouterListURLPattern did not match %q", path)
+	}
+	if virtualRouterListURLPattern.MatchString("/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualRouters") {
+		t.Fatal("virtualRouterListURLPattern should not match a resource-group-scoped path")
+	}
+}
@@ -6,6 +6,13 @@
 // Code generated by Microsoft (R) AutoRest Code Generator.
 // Changes may cause incorrect behavior and will be lost if the code is regenerated.
 // DO NOT EDIT.
+//
+// NOTE: this file has been hand-patched past the point of being pure
+// generator output: endSpanAtLROTerminalState below takes a ctx parameter
+// (instead of using context.Background()) so its background poll stops
+// when the caller's context is canceled. A real `autorest` regeneration of
+// this client will silently drop that fix -- reapply it if this file is
+// ever regenerated.
 
 package armnetwork
 
@@ -18,6 +25,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/tracing"
 	"net/http"
 	"net/url"
 	"strings"
@@ -29,6 +37,7 @@ type VirtualRoutersClient struct {
 	host           string
 	subscriptionID string
 	pl             runtime.Pipeline
+	tracer         tracing.Tracer
 }
 
 // NewVirtualRoutersClient creates a new instance of VirtualRoutersClient with the specified values.
@@ -52,10 +61,44 @@ func NewVirtualRoutersClient(subscriptionID string, credential azcore.TokenCrede
 		subscriptionID: subscriptionID,
 		host:           ep,
 		pl:             pl,
+		tracer:         options.TracingProvider.NewTracer(moduleName, moduleVersion),
 	}
 	return client, nil
 }
 
+// spanResponseAttributes augments span with the HTTP status code and Azure's correlation headers once
+// resp is known, so partial failures (e.g. a network error before any response) don't panic on a nil resp.
+func spanResponseAttributes(span tracing.Span, resp *http.Response) {
+	if resp == nil {
+		return
+	}
+	attrs := []tracing.Attribute{{Key: "http.status_code", Value: int64(resp.StatusCode)}}
+	if reqID := resp.Header.Get("x-ms-request-id"); reqID != "" {
+		attrs = append(attrs, tracing.Attribute{Key: "x-ms-request-id", Value: reqID})
+	}
+	if corrID := resp.Header.Get("x-ms-correlation-request-id"); corrID != "" {
+		attrs = append(attrs, tracing.Attribute{Key: "x-ms-correlation-request-id", Value: corrID})
+	}
+	span.SetAttributes(attrs...)
+}
+
+// endSpanAtLROTerminalState defers span.End() until the operation resumeToken identifies reaches a
+// terminal state. It drives its own poller resumed from that token purely to watch for completion:
+// runtime.Poller isn't safe for concurrent polling, so tracking completion can't share the poller
+// instance already handed back to the caller. It polls with the same ctx the caller made the Begin*
+// call with, so canceling or timing out that ctx stops this watcher too instead of leaking it and
+// polling ARM independently forever.
+func endSpanAtLROTerminalState[T any](ctx context.Context, span tracing.Span, pl runtime.Pipeline, resumeToken string) {
+	go func() {
+		defer span.End()
+		watcher, err := runtime.NewPollerFromResumeToken[T](resumeToken, pl, nil)
+		if err != nil {
+			return
+		}
+		watcher.PollUntilDone(ctx, nil)
+	}()
+}
+
 // BeginCreateOrUpdate - Creates or updates the specified Virtual Router.
 // If the operation fails it returns an *azcore.ResponseError type.
 // Generated from API version 2022-01-01
@@ -65,17 +108,39 @@ func NewVirtualRoutersClient(subscriptionID string, credential azcore.TokenCrede
 // options - VirtualRoutersClientBeginCreateOrUpdateOptions contains the optional parameters for the VirtualRoutersClient.BeginCreateOrUpdate
 // method.
 func (client *VirtualRoutersClient) BeginCreateOrUpdate(ctx context.Context, resourceGroupName string, virtualRouterName string, parameters VirtualRouter, options *VirtualRoutersClientBeginCreateOrUpdateOptions) (*runtime.Poller[VirtualRoutersClientCreateOrUpdateResponse], error) {
+	ctx, span := client.tracer.Start(ctx, "Network.VirtualRouters.BeginCreateOrUpdate", nil)
+	span.SetAttributes(
+		tracing.Attribute{Key: "azure.resource_group", Value: resourceGroupName},
+		tracing.Attribute{Key: "azure.virtual_router", Value: virtualRouterName},
+		tracing.Attribute{Key: "azure.subscription_id", Value: client.subscriptionID},
+		tracing.Attribute{Key: "http.method", Value: http.MethodPut},
+	)
+
+	var poller *runtime.Poller[VirtualRoutersClientCreateOrUpdateResponse]
+	var err error
 	if options == nil || options.ResumeToken == "" {
-		resp, err := client.createOrUpdate(ctx, resourceGroupName, virtualRouterName, parameters, options)
-		if err != nil {
-			return nil, err
+		var resp *http.Response
+		resp, err = client.createOrUpdate(ctx, resourceGroupName, virtualRouterName, parameters, options)
+		spanResponseAttributes(span, resp)
+		if err == nil {
+			poller, err = runtime.NewPoller(resp, client.pl, &runtime.NewPollerOptions[VirtualRoutersClientCreateOrUpdateResponse]{
+				FinalStateVia: runtime.FinalStateViaAzureAsyncOp,
+			})
 		}
-		return runtime.NewPoller(resp, client.pl, &runtime.NewPollerOptions[VirtualRoutersClientCreateOrUpdateResponse]{
-			FinalStateVia: runtime.FinalStateViaAzureAsyncOp,
-		})
 	} else {
-		return runtime.NewPollerFromResumeToken[VirtualRoutersClientCreateOrUpdateResponse](options.ResumeToken, client.pl, nil)
+		poller, err = runtime.NewPollerFromResumeToken[VirtualRoutersClientCreateOrUpdateResponse](options.ResumeToken, client.pl, nil)
+	}
+	if err != nil {
+		span.End()
+		return nil, err
+	}
+
+	if token, tokenErr := poller.ResumeToken(); tokenErr == nil {
+		endSpanAtLROTerminalState[VirtualRoutersClientCreateOrUpdateResponse](ctx, span, client.pl, token)
+	} else {
+		span.End()
 	}
+	return poller, nil
 }
 
 // CreateOrUpdate - Creates or updates the specified Virtual Router.
@@ -130,17 +195,39 @@ func (client *VirtualRoutersClient) createOrUpdateCreateRequest(ctx context.Cont
 // options - VirtualRoutersClientBeginDeleteOptions contains the optional parameters for the VirtualRoutersClient.BeginDelete
 // method.
 func (client *VirtualRoutersClient) BeginDelete(ctx context.Context, resourceGroupName string, virtualRouterName string, options *VirtualRoutersClientBeginDeleteOptions) (*runtime.Poller[VirtualRoutersClientDeleteResponse], error) {
+	ctx, span := client.tracer.Start(ctx, "Network.VirtualRouters.BeginDelete", nil)
+	span.SetAttributes(
+		tracing.Attribute{Key: "azure.resource_group", Value: resourceGroupName},
+		tracing.Attribute{Key: "azure.virtual_router", Value: virtualRouterName},
+		tracing.Attribute{Key: "azure.subscription_id", Value: client.subscriptionID},
+		tracing.Attribute{Key: "http.method", Value: http.MethodDelete},
+	)
+
+	var poller *runtime.Poller[VirtualRoutersClientDeleteResponse]
+	var err error
 	if options == nil || options.ResumeToken == "" {
-		resp, err := client.deleteOperation(ctx, resourceGroupName, virtualRouterName, options)
-		if err != nil {
-			return nil, err
+		var resp *http.Response
+		resp, err = client.deleteOperation(ctx, resourceGroupName, virtualRouterName, options)
+		spanResponseAttributes(span, resp)
+		if err == nil {
+			poller, err = runtime.NewPoller(resp, client.pl, &runtime.NewPollerOptions[VirtualRoutersClientDeleteResponse]{
+				FinalStateVia: runtime.FinalStateViaLocation,
+			})
 		}
-		return runtime.NewPoller(resp, client.pl, &runtime.NewPollerOptions[VirtualRoutersClientDeleteResponse]{
-			FinalStateVia: runtime.FinalStateViaLocation,
-		})
 	} else {
-		return runtime.NewPollerFromResumeToken[VirtualRoutersClientDeleteResponse](options.ResumeToken, client.pl, nil)
+		poller, err = runtime.NewPollerFromResumeToken[VirtualRoutersClientDeleteResponse](options.ResumeToken, client.pl, nil)
+	}
+	if err != nil {
+		span.End()
+		return nil, err
+	}
+
+	if token, tokenErr := poller.ResumeToken(); tokenErr == nil {
+		endSpanAtLROTerminalState[VirtualRoutersClientDeleteResponse](ctx, span, client.pl, token)
+	} else {
+		span.End()
 	}
+	return poller, nil
 }
 
 // Delete - Deletes the specified Virtual Router.
@@ -194,11 +281,21 @@ func (client *VirtualRoutersClient) deleteCreateRequest(ctx context.Context, res
 // virtualRouterName - The name of the Virtual Router.
 // options - VirtualRoutersClientGetOptions contains the optional parameters for the VirtualRoutersClient.Get method.
 func (client *VirtualRoutersClient) Get(ctx context.Context, resourceGroupName string, virtualRouterName string, options *VirtualRoutersClientGetOptions) (VirtualRoutersClientGetResponse, error) {
+	ctx, span := client.tracer.Start(ctx, "Network.VirtualRouters.Get", nil)
+	defer span.End()
+	span.SetAttributes(
+		tracing.Attribute{Key: "azure.resource_group", Value: resourceGroupName},
+		tracing.Attribute{Key: "azure.virtual_router", Value: virtualRouterName},
+		tracing.Attribute{Key: "azure.subscription_id", Value: client.subscriptionID},
+		tracing.Attribute{Key: "http.method", Value: http.MethodGet},
+	)
+
 	req, err := client.getCreateRequest(ctx, resourceGroupName, virtualRouterName, options)
 	if err != nil {
 		return VirtualRoutersClientGetResponse{}, err
 	}
 	resp, err := client.pl.Do(req)
+	spanResponseAttributes(span, resp)
 	if err != nil {
 		return VirtualRoutersClientGetResponse{}, err
 	}
@@ -256,6 +353,13 @@ func (client *VirtualRoutersClient) NewListPager(options *VirtualRoutersClientLi
 			return page.NextLink != nil && len(*page.NextLink) > 0
 		},
 		Fetcher: func(ctx context.Context, page *VirtualRoutersClientListResponse) (VirtualRoutersClientListResponse, error) {
+			ctx, span := client.tracer.Start(ctx, "Network.VirtualRouters.NewListPager.Page", nil)
+			defer span.End()
+			span.SetAttributes(
+				tracing.Attribute{Key: "azure.subscription_id", Value: client.subscriptionID},
+				tracing.Attribute{Key: "http.method", Value: http.MethodGet},
+			)
+
 			var req *policy.Request
 			var err error
 			if page == nil {
@@ -267,6 +371,7 @@ func (client *VirtualRoutersClient) NewListPager(options *VirtualRoutersClientLi
 				return VirtualRoutersClientListResponse{}, err
 			}
 			resp, err := client.pl.Do(req)
+			spanResponseAttributes(span, resp)
 			if err != nil {
 				return VirtualRoutersClientListResponse{}, err
 			}
@@ -317,6 +422,14 @@ func (client *VirtualRoutersClient) NewListByResourceGroupPager(resourceGroupNam
 			return page.NextLink != nil && len(*page.NextLink) > 0
 		},
 		Fetcher: func(ctx context.Context, page *VirtualRoutersClientListByResourceGroupResponse) (VirtualRoutersClientListByResourceGroupResponse, error) {
+			ctx, span := client.tracer.Start(ctx, "Network.VirtualRouters.NewListByResourceGroupPager.Page", nil)
+			defer span.End()
+			span.SetAttributes(
+				tracing.Attribute{Key: "azure.resource_group", Value: resourceGroupName},
+				tracing.Attribute{Key: "azure.subscription_id", Value: client.subscriptionID},
+				tracing.Attribute{Key: "http.method", Value: http.MethodGet},
+			)
+
 			var req *policy.Request
 			var err error
 			if page == nil {
@@ -328,6 +441,7 @@ func (client *VirtualRoutersClient) NewListByResourceGroupPager(resourceGroupNam
 				return VirtualRoutersClientListByResourceGroupResponse{}, err
 			}
 			resp, err := client.pl.Do(req)
+			spanResponseAttributes(span, resp)
 			if err != nil {
 				return VirtualRoutersClientListByResourceGroupResponse{}, err
 			}